@@ -0,0 +1,208 @@
+package algo
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+)
+
+// AhoCorasick finds every occurrence of many patterns at once in a single
+// pass over some text. The patterns go into a Trie, and each node gets a
+// failure link to the node for the longest proper suffix of its word that
+// is also in the trie, so when the next letter doesn't match we can fall
+// back without going over the text again.
+type AhoCorasick struct {
+	trie     *Trie
+	patterns []string
+
+	// The automaton state for each node in the trie
+	states map[*Trie]*acState
+}
+
+// acState is the extra information we need for each trie node
+type acState struct {
+	node *Trie
+
+	// Where to go when the next letter isn't a child of node
+	fail *acState
+
+	// The next state down the failure links that ends a pattern
+	output *acState
+
+	// The patterns that end here
+	ids []int
+
+	// How many bytes the word at this node has
+	length int
+}
+
+// A Match is an occurrence of a pattern in the text. Start and End are byte
+// offsets, so the text from Start up to End is the pattern.
+type Match struct {
+	Pattern    int
+	Start, End int64
+}
+
+// NewAhoCorasick creates a matcher for patterns. Matches refer to the
+// patterns by their index. Empty patterns never match. Text and patterns
+// don't need to be valid UTF-8. A byte that isn't part of a valid rune only
+// matches the same byte, never a real U+FFFD.
+func NewAhoCorasick(patterns []string) *AhoCorasick {
+	ac := &AhoCorasick{
+		trie:     NewTrie(),
+		patterns: patterns,
+		states:   map[*Trie]*acState{},
+	}
+
+	// Remember which patterns end at each node
+	ids := map[*Trie][]int{}
+	for id, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+
+		node := ac.trie
+		for i := 0; i < len(pattern); {
+			letter, size := acDecode(pattern[i:])
+			node, _, _ = node.ensureChild(letter)
+			i += size
+		}
+		node.Value = 1
+
+		ids[node] = append(ids[node], id)
+	}
+
+	root := &acState{node: ac.trie}
+	root.fail = root
+	ac.states[ac.trie] = root
+
+	// Go through the trie breadth first, so the failure link of every
+	// node points to a shorter word that we've already finished
+	queue := []*acState{root}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for child := state.node.Child; child != nil; child = child.Sibling {
+			cs := &acState{
+				node:   child,
+				length: state.length + acLetterLen(child.Letter),
+				fail:   root,
+				ids:    ids[child],
+			}
+
+			// Follow our parent's failure links until we find a
+			// word we can extend with this letter
+			if state != root {
+				cs.fail = ac.next(state.fail, child.Letter)
+			}
+
+			if len(cs.fail.ids) > 0 {
+				cs.output = cs.fail
+			} else {
+				cs.output = cs.fail.output
+			}
+
+			ac.states[child] = cs
+			queue = append(queue, cs)
+		}
+	}
+
+	return ac
+}
+
+// Pattern returns the pattern with this id
+func (ac *AhoCorasick) Pattern(id int) string {
+	return ac.patterns[id]
+}
+
+// acDecode decodes the letter at the start of s, like
+// utf8.DecodeRuneInString. A byte that isn't valid UTF-8 becomes a negative
+// letter of its own, so it can't be mistaken for a real U+FFFD. Returns the
+// letter and how many bytes it takes.
+func acDecode(s string) (rune, int) {
+	letter, size := utf8.DecodeRuneInString(s)
+	if letter == utf8.RuneError && size == 1 {
+		letter = -1 - rune(s[0])
+	}
+
+	return letter, size
+}
+
+// How many bytes a letter from acDecode takes in the text
+func acLetterLen(letter rune) int {
+	if letter < 0 {
+		return 1
+	}
+
+	return utf8.RuneLen(letter)
+}
+
+// next returns the state we get to by reading letter from state
+func (ac *AhoCorasick) next(state *acState, letter rune) *acState {
+	for {
+		child := state.node.findChild(letter)
+		if child != nil {
+			return ac.states[child]
+		}
+
+		if state.fail == state {
+			// We're at the root and nothing starts with letter
+			return state
+		}
+
+		state = state.fail
+	}
+}
+
+// Scan reads all of r and calls f for every match, in order by where they
+// end, until f returns false. Patterns that overlap or contain each other
+// all match. Returns any error from reading r.
+func (ac *AhoCorasick) Scan(r io.Reader, f func(Match) bool) error {
+	var offset int64
+
+	br := bufio.NewReader(r)
+	state := ac.states[ac.trie]
+
+	for {
+		letter, size, err := br.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Read a bad byte again to know which one it was, the same as
+		// acDecode
+		if letter == utf8.RuneError && size == 1 {
+			br.UnreadRune()
+
+			b, err := br.ReadByte()
+			if err != nil {
+				return err
+			}
+
+			letter = -1 - rune(b)
+		}
+
+		offset += int64(size)
+		state = ac.next(state, letter)
+
+		// Report every pattern that ends here, from longest to
+		// shortest
+		for out := state; out != nil; out = out.output {
+			for _, id := range out.ids {
+				m := Match{
+					Pattern: id,
+					Start:   offset - int64(out.length),
+					End:     offset,
+				}
+
+				if !f(m) {
+					return nil
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package algo_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/brnstz/algo"
+)
+
+// Find every match the slow way, in the same order as Scan
+func bruteForceMatches(text string, patterns []string) []algo.Match {
+	var matches []algo.Match
+
+	for end := 1; end <= len(text); end++ {
+		// Longest first, like Scan
+		for length := end; length > 0; length-- {
+			for id, pattern := range patterns {
+				if len(pattern) == length && text[end-length:end] == pattern {
+					matches = append(matches, algo.Match{
+						Pattern: id,
+						Start:   int64(end - length),
+						End:     int64(end),
+					})
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+func TestAhoCorasick(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	// Bytes that are never valid UTF-8 should only match themselves, not
+	// a real U+FFFD
+	letters := []string{"a", "b", "c", "é", "\uFFFD", "\xff", "\xfe"}
+
+	randomString := func(n int) string {
+		var s strings.Builder
+		for i := 0; i < n; i++ {
+			s.WriteString(letters[r.Intn(len(letters))])
+		}
+		return s.String()
+	}
+
+	for round := 0; round < 20; round++ {
+		patterns := []string{""}
+		for i := 0; i < 30; i++ {
+			patterns = append(patterns, randomString(r.Intn(5)+1))
+		}
+		// A dupe should match with both ids
+		patterns = append(patterns, patterns[1])
+
+		text := randomString(500)
+		ac := algo.NewAhoCorasick(patterns)
+
+		var matches []algo.Match
+		err := ac.Scan(iotest.OneByteReader(strings.NewReader(text)), func(m algo.Match) bool {
+			matches = append(matches, m)
+			return true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := bruteForceMatches(text, patterns)
+
+		// Patterns of the same length ending at the same spot are
+		// dupes, which may come in either order
+		sortMatches := func(m []algo.Match) {
+			slices.SortStableFunc(m, func(a, b algo.Match) int {
+				if a.End != b.End {
+					return int(a.End - b.End)
+				}
+				if a.Start != b.Start {
+					return int(a.Start - b.Start)
+				}
+				return a.Pattern - b.Pattern
+			})
+		}
+		sortMatches(matches)
+		sortMatches(expected)
+
+		if !slices.Equal(matches, expected) {
+			t.Fatalf("Expected %v matches but got %v", len(expected), len(matches))
+		}
+
+		for _, m := range matches {
+			if text[m.Start:m.End] != ac.Pattern(m.Pattern) {
+				t.Fatalf("Match %+v is %q, not %q", m, text[m.Start:m.End], ac.Pattern(m.Pattern))
+			}
+		}
+	}
+}
+
+func TestAhoCorasickInvalidUTF8(t *testing.T) {
+	ac := algo.NewAhoCorasick([]string{"\uFFFD", "a\xffb", "\xff"})
+
+	var matches []algo.Match
+	ac.Scan(strings.NewReader("\xffa\xffb\uFFFD"), func(m algo.Match) bool {
+		matches = append(matches, m)
+		return true
+	})
+
+	expected := []algo.Match{
+		{Pattern: 2, Start: 0, End: 1},
+		{Pattern: 2, Start: 2, End: 3},
+		{Pattern: 1, Start: 1, End: 4},
+		{Pattern: 0, Start: 4, End: 7},
+	}
+	if !slices.Equal(matches, expected) {
+		t.Fatalf("Expected %+v but got %+v", expected, matches)
+	}
+}
+
+func TestAhoCorasickOverlap(t *testing.T) {
+	ac := algo.NewAhoCorasick([]string{"he", "she", "his", "hers"})
+
+	var found []string
+	ac.Scan(strings.NewReader("ushers"), func(m algo.Match) bool {
+		found = append(found, ac.Pattern(m.Pattern))
+		return true
+	})
+
+	if strings.Join(found, " ") != "she he hers" {
+		t.Fatalf("Unexpected matches %v", found)
+	}
+
+	// Stopping early
+	count := 0
+	ac.Scan(strings.NewReader("ushers"), func(m algo.Match) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Fatalf("Expected to stop after 1 match but got %v", count)
+	}
+
+	// Errors from the reader come back
+	boom := errors.New("boom")
+	err := ac.Scan(io.MultiReader(strings.NewReader("she"), iotest.ErrReader(boom)), func(m algo.Match) bool {
+		return true
+	})
+	if err != boom {
+		t.Fatalf("Expected boom but got %v", err)
+	}
+}
+
+// Scan a book for thousands of its own words at once
+func BenchmarkAhoCorasick(b *testing.B) {
+	text, err := os.ReadFile("data/tale.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	words := strings.Fields(string(text))
+	patterns := words[:min(5000, len(words))]
+	ac := algo.NewAhoCorasick(patterns)
+
+	b.SetBytes(int64(len(text)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ac.Scan(bytes.NewReader(text), func(m algo.Match) bool {
+			return true
+		})
+	}
+}
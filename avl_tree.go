@@ -0,0 +1,308 @@
+package algo
+
+import (
+	"iter"
+)
+
+// AVLTree is a binary search tree where the heights of the two subtrees of
+// every node differ by at most one. It is more rigidly balanced than a
+// RedBlackTree, so lookups are a little faster and changes are a little
+// slower.
+type AVLTree struct {
+	root *avlNode
+}
+
+// A node on an AVLTree
+type avlNode struct {
+	left  *avlNode
+	right *avlNode
+
+	// Height of the subtree at this node, a leaf is 1
+	height int
+
+	// Number of nodes under this one
+	nodeCount int
+
+	// If we get a dupe value, increment this count
+	valueCount int
+
+	value NodeValue
+}
+
+// Create a new, empty AVL tree
+func NewAVLTree() *AVLTree {
+	return &AVLTree{}
+}
+
+// Put a new value into the tree
+func (t *AVLTree) Put(v NodeValue) {
+	t.root = t.put(t.root, v)
+}
+
+// Recursively put v into the tree, returning potential replacement for n
+func (t *AVLTree) put(n *avlNode, v NodeValue) *avlNode {
+	if n == nil {
+		return &avlNode{
+			value:      v,
+			height:     1,
+			nodeCount:  1,
+			valueCount: 1,
+		}
+	}
+
+	if v.Equals(n.value) {
+		n.valueCount++
+		return n
+	}
+
+	if v.Less(n.value) {
+		n.left = t.put(n.left, v)
+	} else {
+		n.right = t.put(n.right, v)
+	}
+
+	return t.balance(n)
+}
+
+// Can we find value v in the tree?
+func (t *AVLTree) Find(v NodeValue) bool {
+	return t.find(v) != nil
+}
+
+// Find the node holding v or nil
+func (t *AVLTree) find(v NodeValue) *avlNode {
+	n := t.root
+
+	for n != nil {
+		if v.Equals(n.value) {
+			return n
+		}
+
+		if v.Less(n.value) {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+
+	return nil
+}
+
+// Delete one copy of value v from the tree. Returns false if v is not in
+// the tree.
+func (t *AVLTree) Delete(v NodeValue) bool {
+	n := t.find(v)
+	if n == nil {
+		return false
+	}
+
+	// If we have dupes, just decrement the count
+	if n.valueCount > 1 {
+		n.valueCount--
+		return true
+	}
+
+	t.root = t.delete(t.root, v)
+
+	return true
+}
+
+// Recursively delete the node holding v under n, returning potential
+// replacement for n. v must be in the tree.
+func (t *AVLTree) delete(n *avlNode, v NodeValue) *avlNode {
+	if v.Equals(n.value) {
+		// With one child or less, the child takes our place
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+
+		// Otherwise our successor takes our place
+		min := n.right
+		for min.left != nil {
+			min = min.left
+		}
+
+		n.value = min.value
+		n.valueCount = min.valueCount
+		n.right = t.deleteMin(n.right)
+
+	} else if v.Less(n.value) {
+		n.left = t.delete(n.left, v)
+
+	} else {
+		n.right = t.delete(n.right, v)
+	}
+
+	return t.balance(n)
+}
+
+// Recursively delete the smallest node under n, returning potential
+// replacement for n
+func (t *AVLTree) deleteMin(n *avlNode) *avlNode {
+	if n.left == nil {
+		return n.right
+	}
+
+	n.left = t.deleteMin(n.left)
+
+	return t.balance(n)
+}
+
+// How many distinct values are in the tree?
+func (t *AVLTree) Size() int {
+	return t.root.nodeCountZeroNil()
+}
+
+// What is the height of the tree? An empty tree is -1, just like
+// RedBlackTree.
+func (t *AVLTree) Height() int {
+	return t.root.heightZeroNil() - 1
+}
+
+// Smallest value in the tree
+func (t *AVLTree) Min() (NodeValue, error) {
+	if t.root == nil {
+		return nil, EmptyTree
+	}
+
+	n := t.root
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n.value, nil
+}
+
+// Largest value in the tree
+func (t *AVLTree) Max() (NodeValue, error) {
+	if t.root == nil {
+		return nil, EmptyTree
+	}
+
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n.value, nil
+}
+
+// All returns an iterator over every distinct value in the tree, from
+// smallest to largest
+func (t *AVLTree) All() iter.Seq[NodeValue] {
+	return t.Range(nil, nil)
+}
+
+// Range returns an iterator over every distinct value in the tree between
+// lo and hi, inclusive, from smallest to largest
+func (t *AVLTree) Range(lo, hi NodeValue) iter.Seq[NodeValue] {
+	return func(yield func(NodeValue) bool) {
+		t.inOrder(t.root, lo, hi, yield)
+	}
+}
+
+// Recursively yield values under n in order, skipping subtrees that are
+// out of range. Returns false if yield asked us to stop.
+func (t *AVLTree) inOrder(n *avlNode, lo, hi NodeValue, yield func(NodeValue) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	aboveLo := atLeast(n.value, lo)
+	belowHi := atMost(n.value, hi)
+
+	if aboveLo && !t.inOrder(n.left, lo, hi, yield) {
+		return false
+	}
+
+	if aboveLo && belowHi && !yield(n.value) {
+		return false
+	}
+
+	if belowHi && !t.inOrder(n.right, lo, hi, yield) {
+		return false
+	}
+
+	return true
+}
+
+// Restore the AVL property at n, returning potential replacement for n
+func (t *AVLTree) balance(n *avlNode) *avlNode {
+	n.update()
+
+	switch n.balanceFactor() {
+
+	case 2:
+		// Left side is too tall. If its right side is the taller one,
+		// rotate it left first so that a single right rotation fixes it.
+		if n.left.balanceFactor() < 0 {
+			n.left = t.rotateLeft(n.left)
+		}
+		return t.rotateRight(n)
+
+	case -2:
+		// Right side is too tall, mirror of above
+		if n.right.balanceFactor() > 0 {
+			n.right = t.rotateRight(n.right)
+		}
+		return t.rotateLeft(n)
+	}
+
+	return n
+}
+
+// Rotate left at op (old parent), returning the new parent
+func (t *AVLTree) rotateLeft(op *avlNode) *avlNode {
+	np := op.right
+	op.right = np.left
+	np.left = op
+
+	op.update()
+	np.update()
+
+	return np
+}
+
+// Rotate right at op (old parent), returning the new parent
+func (t *AVLTree) rotateRight(op *avlNode) *avlNode {
+	np := op.left
+	op.left = np.right
+	np.right = op
+
+	op.update()
+	np.update()
+
+	return np
+}
+
+// Recalculate height and node count from our children
+func (n *avlNode) update() {
+	n.height = MaxInt(n.left.heightZeroNil(), n.right.heightZeroNil()) + 1
+	n.nodeCount = n.left.nodeCountZeroNil() + n.right.nodeCountZeroNil() + 1
+}
+
+// Height of the left subtree minus height of the right subtree
+func (n *avlNode) balanceFactor() int {
+	return n.left.heightZeroNil() - n.right.heightZeroNil()
+}
+
+// Private helper function to get the height of a possibly nil node
+func (n *avlNode) heightZeroNil() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.height
+}
+
+// Private helper function to get the node count of a possibly nil node
+func (n *avlNode) nodeCountZeroNil() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.nodeCount
+}
@@ -0,0 +1,423 @@
+package algo
+
+import (
+	"iter"
+)
+
+// BTree is an in-memory B-tree. Each node holds many sorted values, which
+// keeps the tree very shallow and makes good use of the CPU cache, at the
+// cost of shifting values within a node on every change.
+type BTree struct {
+	root *btreeNode
+
+	// Minimum degree. Every node except the root has between degree-1
+	// and 2*degree-1 values, and internal nodes have one more child than
+	// values.
+	degree int
+
+	// Number of distinct values in the tree
+	size int
+}
+
+// A node on a BTree
+type btreeNode struct {
+	entries  []btreeEntry
+	children []*btreeNode
+}
+
+// A value in a btreeNode
+type btreeEntry struct {
+	value NodeValue
+
+	// If we get a dupe value, increment this count
+	valueCount int
+}
+
+// Create a new, empty B-tree where each node has at most order children.
+// The order is rounded down to an even number, and must be at least 4.
+func NewBTree(order int) *BTree {
+	degree := order / 2
+	if degree < 2 {
+		degree = 2
+	}
+
+	return &BTree{
+		root:   &btreeNode{},
+		degree: degree,
+	}
+}
+
+// Put a new value into the tree
+func (t *BTree) Put(v NodeValue) {
+	// If it's a dupe, just increment the count
+	if e := t.find(v); e != nil {
+		e.valueCount++
+		return
+	}
+
+	// If the root is full, split it first. This is the only way the tree
+	// gets taller.
+	if t.root.isFull(t.degree) {
+		old := t.root
+		t.root = &btreeNode{children: []*btreeNode{old}}
+		t.splitChild(t.root, 0)
+	}
+
+	t.put(t.root, v)
+	t.size++
+}
+
+// Put v under n, which is not full. v must not be in the tree.
+func (t *BTree) put(n *btreeNode, v NodeValue) {
+	for {
+		i := n.search(v)
+
+		if n.isLeaf() {
+			n.entries = insertAt(n.entries, i, btreeEntry{value: v, valueCount: 1})
+			return
+		}
+
+		// Split a full child before going down into it, so that there is
+		// always room for a value that moves up
+		if n.children[i].isFull(t.degree) {
+			t.splitChild(n, i)
+
+			if n.entries[i].value.Less(v) {
+				i++
+			}
+		}
+
+		n = n.children[i]
+	}
+}
+
+// Split the full child at index i of n into two nodes, moving its middle
+// value up into n
+func (t *BTree) splitChild(n *btreeNode, i int) {
+	child := n.children[i]
+	mid := t.degree - 1
+
+	right := &btreeNode{
+		entries: append([]btreeEntry(nil), child.entries[mid+1:]...),
+	}
+	if !child.isLeaf() {
+		right.children = append([]*btreeNode(nil), child.children[mid+1:]...)
+	}
+
+	n.entries = insertAt(n.entries, i, child.entries[mid])
+	n.children = insertAt(n.children, i+1, right)
+
+	clear(child.entries[mid:])
+	child.entries = child.entries[:mid]
+	if !child.isLeaf() {
+		clear(child.children[mid+1:])
+		child.children = child.children[:mid+1]
+	}
+}
+
+// Can we find value v in the tree?
+func (t *BTree) Find(v NodeValue) bool {
+	return t.find(v) != nil
+}
+
+// Find the entry holding v or nil
+func (t *BTree) find(v NodeValue) *btreeEntry {
+	n := t.root
+
+	for {
+		i := n.search(v)
+
+		if i < len(n.entries) && v.Equals(n.entries[i].value) {
+			return &n.entries[i]
+		}
+
+		if n.isLeaf() {
+			return nil
+		}
+
+		n = n.children[i]
+	}
+}
+
+// Delete one copy of value v from the tree. Returns false if v is not in
+// the tree.
+func (t *BTree) Delete(v NodeValue) bool {
+	e := t.find(v)
+	if e == nil {
+		return false
+	}
+
+	// If we have dupes, just decrement the count
+	if e.valueCount > 1 {
+		e.valueCount--
+		return true
+	}
+
+	t.delete(t.root, v)
+	t.size--
+
+	// If the root ran out of values, its only child is the new root. This
+	// is the only way the tree gets shorter.
+	if len(t.root.entries) == 0 && !t.root.isLeaf() {
+		t.root = t.root.children[0]
+	}
+
+	return true
+}
+
+// Delete the entry holding v under n. v must be under n, and n must have at
+// least degree values unless it is the root.
+func (t *BTree) delete(n *btreeNode, v NodeValue) {
+	for {
+		i := n.search(v)
+		found := i < len(n.entries) && v.Equals(n.entries[i].value)
+
+		if n.isLeaf() {
+			// v must be here
+			n.entries = removeAt(n.entries, i)
+			return
+		}
+
+		if found {
+			left, right := n.children[i], n.children[i+1]
+
+			if len(left.entries) >= t.degree {
+				// Replace v with its predecessor and delete that instead
+				pred := t.maxEntry(left)
+				n.entries[i] = pred
+				n, v = left, pred.value
+
+			} else if len(right.entries) >= t.degree {
+				// Replace v with its successor and delete that instead
+				succ := t.minEntry(right)
+				n.entries[i] = succ
+				n, v = right, succ.value
+
+			} else {
+				// Both children are minimal, so merge them with v in the
+				// middle and delete v from there
+				t.merge(n, i)
+				n = left
+			}
+
+			continue
+		}
+
+		// v is under child i. Make sure it has a spare value before we go
+		// down into it.
+		if len(n.children[i].entries) < t.degree {
+			i = t.fill(n, i)
+		}
+
+		n = n.children[i]
+	}
+}
+
+// Give the child at index i of n at least degree values by borrowing from a
+// sibling or merging with one. Returns the new index of the child.
+func (t *BTree) fill(n *btreeNode, i int) int {
+	child := n.children[i]
+
+	if i > 0 && len(n.children[i-1].entries) >= t.degree {
+		// Borrow through n from the left sibling
+		left := n.children[i-1]
+		last := len(left.entries) - 1
+
+		child.entries = insertAt(child.entries, 0, n.entries[i-1])
+		n.entries[i-1] = left.entries[last]
+		left.entries[last] = btreeEntry{}
+		left.entries = left.entries[:last]
+
+		if !left.isLeaf() {
+			child.children = insertAt(child.children, 0, left.children[last+1])
+			left.children[last+1] = nil
+			left.children = left.children[:last+1]
+		}
+
+		return i
+	}
+
+	if i < len(n.entries) && len(n.children[i+1].entries) >= t.degree {
+		// Borrow through n from the right sibling
+		right := n.children[i+1]
+
+		child.entries = append(child.entries, n.entries[i])
+		n.entries[i] = right.entries[0]
+		right.entries = removeAt(right.entries, 0)
+
+		if !right.isLeaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = removeAt(right.children, 0)
+		}
+
+		return i
+	}
+
+	// Neither sibling can spare a value, merge with one
+	if i < len(n.entries) {
+		t.merge(n, i)
+		return i
+	}
+
+	t.merge(n, i-1)
+	return i - 1
+}
+
+// Merge the child at index i+1 of n and the value at index i of n into the
+// child at index i
+func (t *BTree) merge(n *btreeNode, i int) {
+	left, right := n.children[i], n.children[i+1]
+
+	left.entries = append(left.entries, n.entries[i])
+	left.entries = append(left.entries, right.entries...)
+	left.children = append(left.children, right.children...)
+
+	n.entries = removeAt(n.entries, i)
+	n.children = removeAt(n.children, i+1)
+}
+
+// Smallest entry under n
+func (t *BTree) minEntry(n *btreeNode) btreeEntry {
+	for !n.isLeaf() {
+		n = n.children[0]
+	}
+
+	return n.entries[0]
+}
+
+// Largest entry under n
+func (t *BTree) maxEntry(n *btreeNode) btreeEntry {
+	for !n.isLeaf() {
+		n = n.children[len(n.children)-1]
+	}
+
+	return n.entries[len(n.entries)-1]
+}
+
+// How many distinct values are in the tree?
+func (t *BTree) Size() int {
+	return t.size
+}
+
+// What is the height of the tree? A tree with only a root is 0.
+func (t *BTree) Height() int {
+	height := 0
+
+	for n := t.root; !n.isLeaf(); n = n.children[0] {
+		height++
+	}
+
+	return height
+}
+
+// Smallest value in the tree
+func (t *BTree) Min() (NodeValue, error) {
+	if t.size < 1 {
+		return nil, EmptyTree
+	}
+
+	return t.minEntry(t.root).value, nil
+}
+
+// Largest value in the tree
+func (t *BTree) Max() (NodeValue, error) {
+	if t.size < 1 {
+		return nil, EmptyTree
+	}
+
+	return t.maxEntry(t.root).value, nil
+}
+
+// All returns an iterator over every distinct value in the tree, from
+// smallest to largest
+func (t *BTree) All() iter.Seq[NodeValue] {
+	return t.Range(nil, nil)
+}
+
+// Range returns an iterator over every distinct value in the tree between
+// lo and hi, inclusive, from smallest to largest
+func (t *BTree) Range(lo, hi NodeValue) iter.Seq[NodeValue] {
+	return func(yield func(NodeValue) bool) {
+		t.inOrder(t.root, lo, hi, yield)
+	}
+}
+
+// Recursively yield values under n in order, skipping children that are
+// out of range. Returns false if yield asked us to stop.
+func (t *BTree) inOrder(n *btreeNode, lo, hi NodeValue, yield func(NodeValue) bool) bool {
+	for i := 0; i <= len(n.entries); i++ {
+
+		// Values in child i are all less than entry i, so skip it unless
+		// entry i is above lo
+		if !n.isLeaf() && (i == len(n.entries) || atLeast(n.entries[i].value, lo)) {
+			if !t.inOrder(n.children[i], lo, hi, yield) {
+				return false
+			}
+		}
+
+		if i == len(n.entries) {
+			break
+		}
+
+		v := n.entries[i].value
+
+		// Everything after this is above hi
+		if !atMost(v, hi) {
+			return false
+		}
+
+		if atLeast(v, lo) && !yield(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Index of the first entry in n that is not less than v
+func (n *btreeNode) search(v NodeValue) int {
+	lo, hi := 0, len(n.entries)
+
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		if n.entries[mid].value.Less(v) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo
+}
+
+// Does n have the most values it can hold?
+func (n *btreeNode) isFull(degree int) bool {
+	return len(n.entries) >= 2*degree-1
+}
+
+// Is n at the bottom of the tree?
+func (n *btreeNode) isLeaf() bool {
+	return len(n.children) == 0
+}
+
+// Insert x into s at index i
+func insertAt[T any](s []T, i int, x T) []T {
+	var zero T
+
+	s = append(s, zero)
+	copy(s[i+1:], s[i:])
+	s[i] = x
+
+	return s
+}
+
+// Remove the item at index i from s
+func removeAt[T any](s []T, i int) []T {
+	var zero T
+
+	copy(s[i:], s[i+1:])
+	s[len(s)-1] = zero
+
+	return s[:len(s)-1]
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+	"sync"
+
+	"github.com/brnstz/algo"
+)
+
+var errNoFuzzy = errors.New("fuzzy search is not supported by -impl tst")
+var errNoIndexFile = errors.New("index files are only supported by -impl trie")
+
+// A title we found, with the bitmask of wikis it's in
+type match struct {
+	word  string
+	value int64
+	edits int
+}
+
+// titleIndex holds every title we know about. A few of our data structures
+// can do the job, so we can switch between them to compare.
+type titleIndex interface {
+	// Add a title in the wikis in mask. Returns whether it wasn't a title
+	// before, and how many new nodes were created.
+	add(title string, mask int64) (bool, int)
+
+	// Remove a title from the wikis in mask. Returns whether that left it
+	// in no wikis at all, and how many nodes were removed.
+	remove(title string, mask int64) (bool, int)
+
+	// Look up a word. Returns whether it's a title, the wikis it's in and
+	// up to maxWords titles that start with it.
+	lookup(word string, maxWords int) (bool, int64, []match)
+
+	// Find up to maxWords titles within maxEdits of word
+	fuzzy(word string, maxEdits, maxWords int) ([]match, error)
+
+	// Save the index to w
+	save(w io.Writer) error
+
+	// Replace the index with one read from r
+	load(r io.Reader) error
+
+	// How big is the index?
+	stats() algo.TrieStats
+}
+
+// Create an empty index using the implementation named impl
+func newTitleIndex(impl string) (titleIndex, error) {
+	switch impl {
+	case "trie":
+		return &lockedIndex{titleIndex: &trieIndex{t: algo.NewTrie()}}, nil
+	case "tst":
+		return &lockedIndex{titleIndex: &tstIndex{t: algo.NewTernarySearchTree()}}, nil
+	case "concurrent":
+		return &concurrentIndex{t: algo.NewConcurrentTrie()}, nil
+	default:
+		return nil, errors.New("unknown implementation: " + impl)
+	}
+}
+
+// lockedIndex makes an index that isn't safe for concurrent use safe, by
+// letting in either one writer or any number of readers at a time
+type lockedIndex struct {
+	lock sync.RWMutex
+	titleIndex
+}
+
+func (li *lockedIndex) add(title string, mask int64) (bool, int) {
+	li.lock.Lock()
+	defer li.lock.Unlock()
+
+	return li.titleIndex.add(title, mask)
+}
+
+func (li *lockedIndex) remove(title string, mask int64) (bool, int) {
+	li.lock.Lock()
+	defer li.lock.Unlock()
+
+	return li.titleIndex.remove(title, mask)
+}
+
+func (li *lockedIndex) lookup(word string, maxWords int) (bool, int64, []match) {
+	li.lock.RLock()
+	defer li.lock.RUnlock()
+
+	return li.titleIndex.lookup(word, maxWords)
+}
+
+func (li *lockedIndex) fuzzy(word string, maxEdits, maxWords int) ([]match, error) {
+	li.lock.RLock()
+	defer li.lock.RUnlock()
+
+	return li.titleIndex.fuzzy(word, maxEdits, maxWords)
+}
+
+func (li *lockedIndex) save(w io.Writer) error {
+	li.lock.RLock()
+	defer li.lock.RUnlock()
+
+	return li.titleIndex.save(w)
+}
+
+func (li *lockedIndex) load(r io.Reader) error {
+	li.lock.Lock()
+	defer li.lock.Unlock()
+
+	return li.titleIndex.load(r)
+}
+
+func (li *lockedIndex) stats() algo.TrieStats {
+	li.lock.RLock()
+	defer li.lock.RUnlock()
+
+	return li.titleIndex.stats()
+}
+
+// trieIndex keeps titles in an algo.Trie
+type trieIndex struct {
+	t *algo.Trie
+}
+
+func (ti *trieIndex) add(title string, mask int64) (bool, int) {
+	exists, _ := ti.t.Exists(title)
+
+	nodes, _ := ti.t.Add(title, mask)
+	ti.rank(title)
+
+	return !exists, nodes
+}
+
+func (ti *trieIndex) remove(title string, mask int64) (bool, int) {
+	exists, nodes := ti.t.ClearBits(title, mask)
+	if !exists {
+		return false, 0
+	}
+
+	return !ti.rank(title), nodes
+}
+
+// Titles found in more wikis rank higher in completions. Returns whether
+// it's still a title.
+func (ti *trieIndex) rank(title string) bool {
+	exists, node := ti.t.Exists(title)
+	if exists {
+		ti.t.SetWeight(title, int64(bits.OnesCount64(uint64(node.Value))))
+	}
+
+	return exists
+}
+
+func (ti *trieIndex) lookup(word string, maxWords int) (bool, int64, []match) {
+	var matches []match
+
+	exists, node := ti.t.Exists(word)
+	if node == nil {
+		return false, 0, nil
+	}
+
+	if word != "" {
+		for _, c := range node.FindTopCompletions(word, maxWords) {
+			matches = append(matches, match{word: c.Word, value: c.Node.Value})
+		}
+	}
+
+	return exists, node.Value, matches
+}
+
+func (ti *trieIndex) fuzzy(word string, maxEdits, maxWords int) ([]match, error) {
+	var matches []match
+
+	for _, m := range ti.t.DamerauFuzzySearch(word, maxEdits, maxWords) {
+		matches = append(matches, match{word: m.Word, value: m.Node.Value, edits: m.Edits})
+	}
+
+	return matches, nil
+}
+
+func (ti *trieIndex) save(w io.Writer) error {
+	_, err := ti.t.WriteTo(w)
+
+	return err
+}
+
+func (ti *trieIndex) load(r io.Reader) error {
+	t, err := algo.ReadTrie(r)
+	if err != nil {
+		return err
+	}
+
+	ti.t = t
+
+	return nil
+}
+
+func (ti *trieIndex) stats() algo.TrieStats {
+	return ti.t.Stats()
+}
+
+// tstIndex keeps titles in an algo.TernarySearchTree. It has no weights,
+// so completions come back in alphabetical order, breadth first, instead of
+// ranked by how many wikis they're in. Keep that in mind when comparing its
+// lookup times with the trie, which does more work to rank them.
+type tstIndex struct {
+	t *algo.TernarySearchTree
+}
+
+func (ti *tstIndex) add(title string, mask int64) (bool, int) {
+	exists, _ := ti.t.Exists(title)
+
+	nodes, _ := ti.t.Add(title, mask)
+
+	return !exists, nodes
+}
+
+func (ti *tstIndex) remove(title string, mask int64) (bool, int) {
+	exists, nodes := ti.t.ClearBits(title, mask)
+	if !exists {
+		return false, 0
+	}
+
+	exists, _ = ti.t.Exists(title)
+
+	return !exists, nodes
+}
+
+func (ti *tstIndex) lookup(word string, maxWords int) (bool, int64, []match) {
+	var matches []match
+
+	exists, node := ti.t.Exists(word)
+	if node == nil {
+		return false, 0, nil
+	}
+
+	if word != "" {
+		for _, c := range node.FindCompletions(word, maxWords) {
+			matches = append(matches, match{word: c.Word, value: c.Node.Value})
+		}
+	}
+
+	return exists, node.Value, matches
+}
+
+func (ti *tstIndex) fuzzy(word string, maxEdits, maxWords int) ([]match, error) {
+	return nil, errNoFuzzy
+}
+
+func (ti *tstIndex) save(w io.Writer) error {
+	return errNoIndexFile
+}
+
+func (ti *tstIndex) load(r io.Reader) error {
+	return errNoIndexFile
+}
+
+func (ti *tstIndex) stats() algo.TrieStats {
+	return ti.t.Stats()
+}
+
+// concurrentIndex keeps titles in an algo.ConcurrentTrie, which is already
+// safe for concurrent use, so lookups never wait for writers
+type concurrentIndex struct {
+	// Writers take turns, so checking a title and changing it happen
+	// together. Readers never take it.
+	writeLock sync.Mutex
+
+	t *algo.ConcurrentTrie
+}
+
+func (ci *concurrentIndex) add(title string, mask int64) (bool, int) {
+	ci.writeLock.Lock()
+	defer ci.writeLock.Unlock()
+
+	exists, _ := ci.t.Exists(title)
+
+	nodes, _ := ci.t.Add(title, mask)
+	ci.rank(title)
+
+	return !exists, nodes
+}
+
+func (ci *concurrentIndex) remove(title string, mask int64) (bool, int) {
+	ci.writeLock.Lock()
+	defer ci.writeLock.Unlock()
+
+	exists, nodes := ci.t.ClearBits(title, mask)
+	if !exists {
+		return false, 0
+	}
+
+	return !ci.rank(title), nodes
+}
+
+// Titles found in more wikis rank higher in completions. The weight is
+// worked out from the value with the write lock, so another writer can't
+// change the value in between. Returns whether it's still a title.
+func (ci *concurrentIndex) rank(title string) bool {
+	return ci.t.SetWeightFunc(title, func(value int64) int64 {
+		return int64(bits.OnesCount64(uint64(value)))
+	})
+}
+
+func (ci *concurrentIndex) lookup(word string, maxWords int) (bool, int64, []match) {
+	var matches []match
+
+	exists, node := ci.t.Exists(word)
+	if node == nil {
+		return false, 0, nil
+	}
+
+	if word != "" {
+		for _, c := range node.FindTopCompletions(word, maxWords) {
+			matches = append(matches, match{word: c.Word, value: c.Node.Value()})
+		}
+	}
+
+	return exists, node.Value(), matches
+}
+
+func (ci *concurrentIndex) fuzzy(word string, maxEdits, maxWords int) ([]match, error) {
+	var matches []match
+
+	for _, m := range ci.t.DamerauFuzzySearch(word, maxEdits, maxWords) {
+		matches = append(matches, match{word: m.Word, value: m.Node.Value(), edits: m.Edits})
+	}
+
+	return matches, nil
+}
+
+func (ci *concurrentIndex) save(w io.Writer) error {
+	return errNoIndexFile
+}
+
+func (ci *concurrentIndex) load(r io.Reader) error {
+	return errNoIndexFile
+}
+
+func (ci *concurrentIndex) stats() algo.TrieStats {
+	return ci.t.Stats()
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Run this with -race to make sure requests can read while titles load
+func TestConcurrentRequests(t *testing.T) {
+	masks := map[string]int64{"en": 1, "de": 2}
+
+	for _, impl := range []string{"trie", "tst", "concurrent"} {
+		index, err := newTitleIndex(impl)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+
+		// Writers load titles and delete some of them
+		for w, wiki := range []string{"en", "de"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := 0; i < 2000; i++ {
+					title := fmt.Sprintf("Title %v", i)
+					add(index, title, masks[wiki])
+					if i%10 == w {
+						remove(index, title, masks[wiki])
+					}
+				}
+			}()
+		}
+
+		// Readers make requests at the same time
+		for r := 0; r < 4; r++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := 0; i < 200; i++ {
+					w := httptest.NewRecorder()
+					req := httptest.NewRequest("GET", fmt.Sprintf("/api/word?word=Title+%v", i), nil)
+					getWord(index, masks, w, req)
+
+					var response wordResponse
+					err := json.Unmarshal(w.Body.Bytes(), &response)
+					if err != nil {
+						t.Errorf("%v: can't unmarshal %q: %v", impl, w.Body.String(), err)
+						return
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		// Title 1 was deleted from de but not en
+		exists, value, _ := index.lookup("Title 1", 10)
+		if !exists || value != masks["en"] {
+			t.Fatalf("%v: expected Title 1 only in en but got %v, %v", impl, exists, value)
+		}
+
+		exists, value, completions := index.lookup("Title 3", 10)
+		if !exists || value != masks["en"]|masks["de"] || len(completions) != 10 {
+			t.Fatalf("%v: unexpected lookup %v, %v, %v", impl, exists, value, completions)
+		}
+	}
+}
+
+// Removing a title from one wiki shouldn't count it as gone while it's
+// still in another
+func TestRemoveCounts(t *testing.T) {
+	for _, impl := range []string{"trie", "tst", "concurrent"} {
+		index, err := newTitleIndex(impl)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		titles.Store(0)
+		totalLetters.Store(0)
+		totalNodes.Store(0)
+
+		// The same title in two wikis is one title
+		add(index, "Title", 1)
+		add(index, "Title", 2)
+		if titles.Load() != 1 || totalLetters.Load() != 5 {
+			t.Fatalf("%v: expected 1 title and 5 letters but got %v and %v", impl, titles.Load(), totalLetters.Load())
+		}
+
+		// Clearing a bit that isn't set, or one of two, leaves it
+		remove(index, "Title", 4)
+		remove(index, "Title", 1)
+		if titles.Load() != 1 || totalLetters.Load() != 5 {
+			t.Fatalf("%v: expected 1 title and 5 letters but got %v and %v", impl, titles.Load(), totalLetters.Load())
+		}
+
+		remove(index, "Title", 2)
+		if titles.Load() != 0 || totalLetters.Load() != 0 {
+			t.Fatalf("%v: expected 0 titles and 0 letters but got %v and %v", impl, titles.Load(), totalLetters.Load())
+		}
+
+		// Every node it added is pruned
+		if totalNodes.Load() != 0 {
+			t.Fatalf("%v: expected 0 nodes but got %v", impl, totalNodes.Load())
+		}
+
+		// It's already gone
+		remove(index, "Title", 2)
+		if titles.Load() != 0 || totalLetters.Load() != 0 {
+			t.Fatalf("%v: expected 0 titles and 0 letters but got %v and %v", impl, titles.Load(), totalLetters.Load())
+		}
+	}
+}
+
+// Fuzzy search should work everywhere but tst
+func TestFuzzy(t *testing.T) {
+	for _, impl := range []string{"trie", "tst", "concurrent"} {
+		index, err := newTitleIndex(impl)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		index.add("Paris", 1)
+		index.add("Parish", 2)
+		index.add("Prague", 1)
+
+		matches, err := index.fuzzy("Pairs", 1, 10)
+		if impl == "tst" {
+			if err != errNoFuzzy {
+				t.Fatalf("%v: expected no fuzzy error but got %v", impl, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: %v", impl, err)
+		}
+
+		if len(matches) != 1 || matches[0].word != "Paris" || matches[0].value != 1 || matches[0].edits != 1 {
+			t.Fatalf("%v: unexpected matches %+v", impl, matches)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"compress/bzip2"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -11,16 +12,17 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/brnstz/algo"
 )
 
 const (
 	loadLogInterval = 1000000
 	maxCompletions  = 25
+	maxFuzzyEdits   = 3
 	queueBufferSize = 100
 	dumpDate        = "20170820"
 	wikiIndexURL    = "http://dumps.wikimedia.your.org/%vwiki/%v/%vwiki-%v-pages-articles-multistream-index.txt.bz2"
@@ -37,10 +39,12 @@ const (
 type completion struct {
 	Word  string   `json:"word"`
 	Wikis []string `json:"wikis"`
+	Edits int      `json:"edits,omitempty"`
 }
 type wordResponse struct {
 	Exists      bool         `json:"exists"`
 	Completions []completion `json:"completions"`
+	Fuzzy       []completion `json:"fuzzy,omitempty"`
 	Wikis       []string     `json:"wikis"`
 	Nodes       int          `json:"nodes"`
 	Titles      int          `json:"titles"`
@@ -56,11 +60,11 @@ type dlReq struct {
 type wikiStream struct {
 	Title      string `json:"title"`
 	ServerName string `json:"server_name"`
+	LogType    string `json:"log_type"`
+	LogAction  string `json:"log_action"`
 }
 
-func loadStream(masks map[string]int64, t *algo.Trie) {
-	var ws wikiStream
-
+func loadStream(masks map[string]int64, index titleIndex) {
 	// Continue forever if we are disconnected
 	for {
 		func() {
@@ -81,6 +85,7 @@ func loadStream(masks map[string]int64, t *algo.Trie) {
 
 				// Check if we have the "data: " prefix
 				if strings.HasPrefix(text, streamDataPrefix) {
+					var ws wikiStream
 
 					// Read the JSON data after the prefix until the
 					// end of the line.
@@ -99,14 +104,19 @@ func loadStream(masks map[string]int64, t *algo.Trie) {
 						continue
 					}
 
-					add(t, ws.Title, mask)
+					// Deleted pages are retired from this wiki
+					if ws.LogType == "delete" && ws.LogAction == "delete" {
+						remove(index, ws.Title, mask)
+					} else {
+						add(index, ws.Title, mask)
+					}
 				}
 			}
 		}()
 	}
 }
 
-func download(reqs chan dlReq, t *algo.Trie) {
+func download(reqs chan dlReq, index titleIndex) {
 
 	for req := range reqs {
 		var body io.Reader
@@ -127,7 +137,7 @@ func download(reqs chan dlReq, t *algo.Trie) {
 			resp, err := http.Get(url)
 			if err != nil {
 				log.Printf("can't download %v: %v\n", url, err)
-				return
+				continue
 			}
 			defer resp.Body.Close()
 			body = resp.Body
@@ -139,7 +149,7 @@ func download(reqs chan dlReq, t *algo.Trie) {
 		for s.Scan() {
 			parts := strings.SplitN(s.Text(), ":", titleField)
 			if len(parts) == titleField {
-				add(t, parts[titleField-1], req.mask)
+				add(index, parts[titleField-1], req.mask)
 			}
 			i++
 		}
@@ -159,31 +169,54 @@ func findWikis(masks map[string]int64, value int64) []string {
 	return wikis
 }
 
-func getWord(t *algo.Trie, masks map[string]int64, w http.ResponseWriter, r *http.Request) {
-	var node *algo.Trie
+func getWord(index titleIndex, masks map[string]int64, w http.ResponseWriter, r *http.Request) {
+	var (
+		value   int64
+		matches []match
+	)
 	t1 := time.Now()
 
 	response := wordResponse{
-		Titles:  titles,
-		Nodes:   totalNodes,
-		Letters: totalLetters,
+		Titles:  int(titles.Load()),
+		Nodes:   int(totalNodes.Load()),
+		Letters: int(totalLetters.Load()),
 	}
 	word := r.FormValue("word")
 
-	response.Exists, node = t.Exists(word)
-	if node != nil && node.Letter != 0 {
-		rawCompletions := node.FindCompletions(word, maxCompletions)
-		for _, rawCompletion := range rawCompletions {
-			completion := completion{
-				Word:  rawCompletion.Word,
-				Wikis: findWikis(masks, rawCompletion.Node.Value),
-			}
-			response.Completions = append(response.Completions, completion)
+	response.Exists, value, matches = index.lookup(word, maxCompletions)
+	for _, m := range matches {
+		completion := completion{
+			Word:  m.word,
+			Wikis: findWikis(masks, m.value),
 		}
+		response.Completions = append(response.Completions, completion)
+	}
+
+	if value != 0 {
+		response.Wikis = findWikis(masks, value)
 	}
 
-	if node != nil {
-		response.Wikis = findWikis(masks, node.Value)
+	// Optionally look for titles within some number of typos
+	if r.FormValue("fuzzy") != "" {
+		edits, err := strconv.Atoi(r.FormValue("fuzzy"))
+		if err != nil || edits < 0 || edits > maxFuzzyEdits {
+			http.Error(w, fmt.Sprintf("fuzzy must be between 0 and %v", maxFuzzyEdits), http.StatusBadRequest)
+			return
+		}
+
+		matches, err = index.fuzzy(word, edits, maxCompletions)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		for _, m := range matches {
+			response.Fuzzy = append(response.Fuzzy, completion{
+				Word:  m.word,
+				Wikis: findWikis(masks, m.value),
+				Edits: m.edits,
+			})
+		}
 	}
 
 	response.Time = fmt.Sprintf("%v", time.Now().Sub(t1))
@@ -198,62 +231,167 @@ func getWord(t *algo.Trie, masks map[string]int64, w http.ResponseWriter, r *htt
 	w.Write(b)
 }
 
-var (
-	writeLock sync.Mutex
+// Counts of what we've loaded. These are updated while requests read them,
+// so they're atomic.
+var totalNodes, totalLetters, titles atomic.Int64
 
-	totalNodes, totalLetters, nodes, letters, titles int
-)
+func add(index titleIndex, title string, mask int64) {
+	// Add to our index
+	isNew, nodes := index.add(title, mask)
+	totalNodes.Add(int64(nodes))
+
+	// Only count each title once, no matter how many wikis it's in
+	if !isNew {
+		return
+	}
+
+	totalLetters.Add(int64(len(title)))
+
+	if n := titles.Add(1); n%loadLogInterval == 0 {
+		log.Printf("loaded %v titles", n)
+		log.Printf("letters:      %v", totalLetters.Load())
+		log.Printf("nodes:        %v", totalNodes.Load())
+	}
+}
+
+func remove(index titleIndex, title string, mask int64) {
+	// Remove from our index
+	gone, nodes := index.remove(title, mask)
+	totalNodes.Add(-int64(nodes))
+
+	// Only count it as gone once it isn't in any wiki
+	if gone {
+		titles.Add(-1)
+		totalLetters.Add(-int64(len(title)))
+	}
+}
+
+// Load an index saved by saveIndex. We read the whole thing into a Trie
+// instead of querying the file with algo.MapTrie, because a MappedTrie is
+// read-only and we keep adding and removing titles from the stream after
+// we start.
+func loadIndex(index titleIndex, path string) error {
+	fh, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	err = index.load(bufio.NewReader(fh))
+	if err != nil {
+		return err
+	}
 
-func add(t *algo.Trie, title string, mask int64) {
-	// Add to our trie
-	writeLock.Lock()
+	stats := index.stats()
+	titles.Store(int64(stats.Words))
+	totalNodes.Store(int64(stats.Nodes))
 
-	titles += 1
-	totalLetters += len(title)
-	nodes, _ = t.Add(title, mask)
-	totalNodes += nodes
+	log.Printf("loaded %v titles from %v", stats.Words, path)
+
+	return nil
+}
+
+// Save the index so we can start from it next time
+func saveIndex(index titleIndex, path string) {
+	// Write to a temp file and move it into place so we never leave a
+	// partial index behind
+	fh, err := os.Create(path + ".tmp")
+	if err != nil {
+		log.Printf("can't save index: %v", err)
+		return
+	}
+	defer fh.Close()
 
-	if titles%loadLogInterval == 0 {
-		log.Printf("loaded %v titles", titles)
-		log.Printf("letters:      %v", totalLetters)
-		log.Printf("nodes:        %v", totalNodes)
+	err = index.save(fh)
+	if err == nil {
+		err = fh.Close()
+	}
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		os.Remove(path + ".tmp")
+		log.Printf("can't save index: %v", err)
+		return
 	}
 
-	writeLock.Unlock()
+	log.Printf("saved index to %v", path)
 }
 
 func main() {
-	// Create our global trie
-	trie := algo.NewTrie()
+	indexPath := flag.String("index", "", "load titles from this index file if it exists, otherwise create it after downloading (trie only)")
+	impl := flag.String("impl", "trie", "data structure to keep titles in: trie, tst (completions not ranked) or concurrent")
+	flag.Parse()
 
 	// The list of wikis we want to download
 	wikis := strings.Split(wikiCodes, "|")
 
-	// Create a channel to concurrently download wikis
-	dlChan := make(chan dlReq, len(wikis))
-
 	// Map wiki to a bitmask
 	wikiMasks := map[string]int64{}
-
-	// Send the code and bitmask for each wiki to the downloader
 	var mask int64 = 1
 	for _, wiki := range wikis {
-		dlChan <- dlReq{wiki: wiki, mask: mask}
 		wikiMasks[wiki] = mask
 		mask = mask << 1
 	}
 
-	// Create concurrent workers
-	for i := 0; i < downloadWorkers; i++ {
-		go download(dlChan, trie)
+	// Only the trie can be saved, so say so now instead of after we've
+	// downloaded everything
+	if *indexPath != "" && *impl != "trie" {
+		log.Fatal(errNoIndexFile)
+	}
+
+	// Create our global index
+	index, err := newTitleIndex(*impl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Start from an index file if we have one
+	loaded := false
+	if *indexPath != "" {
+		err = loadIndex(index, *indexPath)
+		if err == nil {
+			loaded = true
+		} else if !os.IsNotExist(err) {
+			log.Printf("can't load index: %v", err)
+		}
+	}
+
+	if !loaded {
+		// Create a channel to concurrently download wikis
+		dlChan := make(chan dlReq, len(wikis))
+
+		// Send the code and bitmask for each wiki to the downloader
+		for _, wiki := range wikis {
+			dlChan <- dlReq{wiki: wiki, mask: wikiMasks[wiki]}
+		}
+		close(dlChan)
+
+		// Create concurrent workers
+		var wg sync.WaitGroup
+		for i := 0; i < downloadWorkers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				download(dlChan, index)
+			}()
+		}
+
+		// Save an index once everything is downloaded
+		if *indexPath != "" {
+			go func() {
+				wg.Wait()
+				saveIndex(index, *indexPath)
+			}()
+		}
 	}
 
 	// Load the live stream
-	go loadStream(wikiMasks, trie)
+	go loadStream(wikiMasks, index)
 
 	mux := http.DefaultServeMux
 	mux.HandleFunc("/api/word", func(w http.ResponseWriter, r *http.Request) {
-		getWord(trie, wikiMasks, w, r)
+		getWord(index, wikiMasks, w, r)
 	})
 	mux.Handle("/", http.FileServer(http.Dir("static")))
 
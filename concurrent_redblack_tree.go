@@ -0,0 +1,105 @@
+package algo
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentRedBlackTree is a RedBlackTree that is safe for concurrent use.
+// Writers take turns creating a new PersistentRedBlackTree and swapping it
+// in as the current version. Readers never wait: each read works on
+// whichever version was current when it started, so iterating with All or
+// Range sees a consistent snapshot even while writers keep going.
+//
+// The zero value is an empty tree.
+type ConcurrentRedBlackTree struct {
+	// Only one writer at a time
+	writeLock sync.Mutex
+
+	// The current version of the tree
+	current atomic.Pointer[PersistentRedBlackTree]
+}
+
+// Create a new, empty concurrent tree
+func NewConcurrentRedBlackTree() *ConcurrentRedBlackTree {
+	return &ConcurrentRedBlackTree{}
+}
+
+// Snapshot returns the current version of the tree, which will never change
+func (t *ConcurrentRedBlackTree) Snapshot() PersistentRedBlackTree {
+	p := t.current.Load()
+	if p == nil {
+		return PersistentRedBlackTree{}
+	}
+
+	return *p
+}
+
+// Put a new value into the tree
+func (t *ConcurrentRedBlackTree) Put(v NodeValue) {
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+
+	next := t.Snapshot().Put(v)
+	t.current.Store(&next)
+}
+
+// Delete one copy of value v from the tree. Returns false if v is not in
+// the tree.
+func (t *ConcurrentRedBlackTree) Delete(v NodeValue) bool {
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+
+	next, ok := t.Snapshot().Delete(v)
+	if ok {
+		t.current.Store(&next)
+	}
+
+	return ok
+}
+
+// Can we find value v in the tree?
+func (t *ConcurrentRedBlackTree) Find(v NodeValue) bool {
+	return t.Snapshot().Find(v)
+}
+
+// How many distinct values are in the tree?
+func (t *ConcurrentRedBlackTree) Size() int {
+	return t.Snapshot().Size()
+}
+
+// Smallest value in the tree
+func (t *ConcurrentRedBlackTree) Min() (NodeValue, error) {
+	return t.Snapshot().Min()
+}
+
+// Largest value in the tree
+func (t *ConcurrentRedBlackTree) Max() (NodeValue, error) {
+	return t.Snapshot().Max()
+}
+
+// All returns an iterator over every distinct value in the tree, from
+// smallest to largest, as of when iteration starts
+func (t *ConcurrentRedBlackTree) All() iter.Seq[NodeValue] {
+	return func(yield func(NodeValue) bool) {
+		for v := range t.Snapshot().All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over every distinct value in the tree between
+// lo and hi, inclusive, from smallest to largest, as of when iteration
+// starts
+func (t *ConcurrentRedBlackTree) Range(lo, hi NodeValue) iter.Seq[NodeValue] {
+	return func(yield func(NodeValue) bool) {
+		for v := range t.Snapshot().Range(lo, hi) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package algo_test
+
+import (
+	"github.com/brnstz/algo"
+
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// Hammer the tree with concurrent writers and readers. Run with -race.
+func TestConcurrentRedBlackTree(t *testing.T) {
+	numWriters := 4
+	numReaders := 4
+	numVals := 2000
+
+	tree := algo.NewConcurrentRedBlackTree()
+
+	var writers, readers sync.WaitGroup
+	done := make(chan struct{})
+
+	// Each writer puts its own values, deleting every other one
+	for w := 0; w < numWriters; w++ {
+		writers.Add(1)
+		go func(w int) {
+			defer writers.Done()
+
+			for _, i := range rand.Perm(numVals) {
+				v := intValue(i*numWriters + w)
+				tree.Put(v)
+
+				if i%2 == 0 && !tree.Delete(v) {
+					t.Errorf("Can't delete %v", v)
+				}
+			}
+		}(w)
+	}
+
+	for r := 0; r < numReaders; r++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				tree.Find(intValue(rand.Intn(numVals * numWriters)))
+
+				// Every range should be in order
+				last := intValue(-1)
+				for v := range tree.Range(intValue(100), intValue(1000)) {
+					if v.(intValue) <= last || v.(intValue) < 100 || v.(intValue) > 1000 {
+						t.Errorf("Unexpected value %v after %v", v, last)
+						return
+					}
+					last = v.(intValue)
+				}
+			}
+		}()
+	}
+
+	writers.Wait()
+	close(done)
+	readers.Wait()
+
+	// Only the odd values should be left
+	if tree.Size() != numVals*numWriters/2 {
+		t.Fatalf("Expected %v values but got %v", numVals*numWriters/2, tree.Size())
+	}
+
+	for v := range tree.All() {
+		if (int(v.(intValue))/numWriters)%2 == 0 {
+			t.Fatalf("Found deleted value %v", v)
+		}
+	}
+}
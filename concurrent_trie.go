@@ -0,0 +1,391 @@
+package algo
+
+import (
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ConcurrentTrie is a Trie that is safe for concurrent use. Writers take
+// turns, but readers never wait. Each node keeps its children in a sorted
+// slice that is never changed once it's published. To add or remove a
+// child, a writer copies the slice, changes the copy and swaps it in, so a
+// reader always sees either the old children or the new ones.
+//
+// That copy makes writes cost more than in a Trie. Adding or removing a
+// child copies all of its siblings, so filling a node with n children
+// copies O(n^2) pointers and leaves the old slices as garbage. On titles
+// with thousands of letters at the top, building a ConcurrentTrie takes
+// about 2.5 times as long as a Trie behind a lock, and allocates 3 times
+// as much (see BenchmarkConcurrentTrieAdd). Use it when reads far outnumber
+// writes.
+//
+// Readers may see some writes and not others. For example, a reader may
+// see a new word before its value is set, in which case it is not a word
+// yet.
+type ConcurrentTrie struct {
+	// Only one writer at a time
+	writeLock sync.Mutex
+
+	root ConcurrentTrieNode
+}
+
+// ConcurrentTrieNode is a node in a ConcurrentTrie
+type ConcurrentTrieNode struct {
+	// The letter this node represents
+	Letter rune
+
+	// A bitmask, the same as Trie.Value
+	value atomic.Int64
+
+	// The weight of this word and the highest weight at or below it, the
+	// same as in Trie
+	weight    atomic.Int64
+	maxWeight atomic.Int64
+
+	// The children of this node in order by letter
+	children atomic.Pointer[[]*ConcurrentTrieNode]
+}
+
+// ConcurrentCompletion is a word found below a ConcurrentTrieNode and the
+// node it ends at, like Completion
+type ConcurrentCompletion struct {
+	Word string
+	Node *ConcurrentTrieNode
+}
+
+func newConcurrentCompletion(word string, node *ConcurrentTrieNode) ConcurrentCompletion {
+	return ConcurrentCompletion{Word: word, Node: node}
+}
+
+// NewConcurrentTrie creates a new, empty concurrent trie
+func NewConcurrentTrie() *ConcurrentTrie {
+	return &ConcurrentTrie{}
+}
+
+// Value is the bitmask for this node. A zero value means it's not a word.
+func (n *ConcurrentTrieNode) Value() int64 {
+	return n.value.Load()
+}
+
+// Weight ranks this word against others in FindTopCompletions
+func (n *ConcurrentTrieNode) Weight() int64 {
+	return n.weight.Load()
+}
+
+// Concurrent trie nodes work with the searches in trie_node.go
+
+func (n *ConcurrentTrieNode) nodeLetter() rune     { return n.Letter }
+func (n *ConcurrentTrieNode) nodeValue() int64     { return n.Value() }
+func (n *ConcurrentTrieNode) nodeWeight() int64    { return n.Weight() }
+func (n *ConcurrentTrieNode) nodeMaxWeight() int64 { return n.maxWeight.Load() }
+
+func (n *ConcurrentTrieNode) nodeBytes() int {
+	return int(unsafe.Sizeof(*n)) + cap(n.childList())*int(unsafe.Sizeof(n))
+}
+
+func (n *ConcurrentTrieNode) nodeChildren() iter.Seq[*ConcurrentTrieNode] {
+	return slices.Values(n.childList())
+}
+
+// The current children of n
+func (n *ConcurrentTrieNode) childList() []*ConcurrentTrieNode {
+	children := n.children.Load()
+	if children == nil {
+		return nil
+	}
+
+	return *children
+}
+
+// Find where letter is or would be in children
+func searchChildren(children []*ConcurrentTrieNode, letter rune) (int, bool) {
+	lo, hi := 0, len(children)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		switch l := children[mid].Letter; {
+		case l == letter:
+			return mid, true
+		case l < letter:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return lo, false
+}
+
+// findChild finds the node for this rune one level below n or returns nil
+func (n *ConcurrentTrieNode) findChild(letter rune) *ConcurrentTrieNode {
+	children := n.childList()
+
+	i, found := searchChildren(children, letter)
+	if !found {
+		return nil
+	}
+
+	return children[i]
+}
+
+// ensureChild ensures that a node for this letter exists one level below n.
+// Returns the node itself, whether this node was newly created, and how
+// many siblings the node has. Only call it with the write lock.
+func (n *ConcurrentTrieNode) ensureChild(letter rune) (*ConcurrentTrieNode, bool, int) {
+	children := n.childList()
+
+	i, found := searchChildren(children, letter)
+	if found {
+		return children[i], false, len(children) - 1
+	}
+
+	// Publish a copy of the children with the new one in it
+	child := &ConcurrentTrieNode{Letter: letter}
+	next := slices.Insert(slices.Clone(children), i, child)
+	n.children.Store(&next)
+
+	return child, true, len(children)
+}
+
+// Add a word to the trie. Returns how many new nodes were created, and the
+// maximum number of siblings a node has.
+func (t *ConcurrentTrie) Add(word string, value int64) (int, int) {
+	var newNodes, maxSiblings int
+
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+
+	node := &t.root
+
+	for _, letter := range word {
+		child, newNode, siblings := node.ensureChild(letter)
+
+		if newNode {
+			newNodes++
+		}
+
+		if siblings > maxSiblings {
+			maxSiblings = siblings
+		}
+
+		node = child
+	}
+
+	// Set new value of this node by running OR on existing value
+	node.value.Or(value)
+
+	return newNodes, maxSiblings
+}
+
+// Exists returns a boolean indicating whether this word exists or not in our
+// trie. It also returns the node when found.
+func (t *ConcurrentTrie) Exists(word string) (bool, *ConcurrentTrieNode) {
+	node := &t.root
+
+	for _, letter := range word {
+		node = node.findChild(letter)
+		if node == nil {
+			return false, nil
+		}
+	}
+
+	return node.Value() > 0, node
+}
+
+// path returns every node from the root to the end of word, or nil if word
+// isn't in the trie
+func (t *ConcurrentTrie) path(word string) []*ConcurrentTrieNode {
+	path := []*ConcurrentTrieNode{&t.root}
+	node := &t.root
+
+	for _, letter := range word {
+		node = node.findChild(letter)
+		if node == nil {
+			return nil
+		}
+
+		path = append(path, node)
+	}
+
+	return path
+}
+
+// Remove a word from the trie, pruning any nodes that no longer lead to a
+// word. Returns whether the word existed and how many nodes were removed.
+func (t *ConcurrentTrie) Remove(word string) (bool, int) {
+	return t.SetValue(word, 0)
+}
+
+// SetValue replaces the value of a word that is already in the trie. Setting
+// it to zero removes the word. Returns whether the word existed and how many
+// nodes were removed.
+func (t *ConcurrentTrie) SetValue(word string, value int64) (bool, int) {
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+
+	return t.setValue(word, func(int64) int64 { return value })
+}
+
+// ClearBits turns off bits in the value of a word that is already in the
+// trie. If no bits are left, the word is removed. Returns whether the word
+// existed and how many nodes were removed.
+func (t *ConcurrentTrie) ClearBits(word string, bits int64) (bool, int) {
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+
+	return t.setValue(word, func(old int64) int64 { return old &^ bits })
+}
+
+// Replace the value of a word with f of its old value, with the write lock
+func (t *ConcurrentTrie) setValue(word string, f func(int64) int64) (bool, int) {
+	var removed int
+
+	path := t.path(word)
+	if path == nil {
+		return false, 0
+	}
+
+	node := path[len(path)-1]
+	if node.Value() == 0 {
+		return false, 0
+	}
+
+	oldWeight := node.Weight()
+
+	value := f(node.Value())
+	node.value.Store(value)
+	if value == 0 {
+		node.weight.Store(0)
+	}
+	newWeight := node.Weight()
+
+	// Unlink nodes that aren't words and have no children, starting at
+	// the end of the word. Never remove the root.
+	for i := len(path) - 1; i > 0; i-- {
+		node = path[i]
+		if node.Value() != 0 || len(node.childList()) > 0 {
+			break
+		}
+
+		parent := path[i-1]
+		children := parent.childList()
+		j, _ := searchChildren(children, node.Letter)
+
+		next := slices.Delete(slices.Clone(children), j, j+1)
+		parent.children.Store(&next)
+		removed++
+	}
+
+	updateConcurrentMaxWeights(path[:len(path)-removed], oldWeight, newWeight)
+
+	return true, removed
+}
+
+// SetWeight sets the weight of a word that is already in the trie. Returns
+// whether the word existed.
+func (t *ConcurrentTrie) SetWeight(word string, weight int64) bool {
+	return t.SetWeightFunc(word, func(int64) int64 { return weight })
+}
+
+// SetWeightFunc sets the weight of a word that is already in the trie to f
+// of its value. f is called with the write lock, so no other writer can
+// change the value before the weight is set. Returns whether the word
+// existed.
+func (t *ConcurrentTrie) SetWeightFunc(word string, f func(value int64) int64) bool {
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+
+	path := t.path(word)
+	if path == nil || path[len(path)-1].Value() == 0 {
+		return false
+	}
+
+	node := path[len(path)-1]
+	oldWeight := node.Weight()
+
+	weight := f(node.Value())
+	node.weight.Store(weight)
+	updateConcurrentMaxWeights(path, oldWeight, weight)
+
+	return true
+}
+
+// updateConcurrentMaxWeights fixes the highest weight under each node in
+// path after the weight of the word at the bottom changed, the same way as
+// updateMaxWeights
+func updateConcurrentMaxWeights(path []*ConcurrentTrieNode, from, to int64) {
+	for i := len(path) - 1; i >= 0; i-- {
+		node := path[i]
+		maxWeight := node.maxWeight.Load()
+
+		var newMax int64
+		switch {
+		case to >= maxWeight:
+			newMax = to
+		case from < maxWeight:
+			// Something else here was already higher
+			return
+		default:
+			newMax = 0
+			if node.Value() > 0 {
+				newMax = node.Weight()
+			}
+
+			for _, child := range node.childList() {
+				newMax = max(newMax, child.maxWeight.Load())
+			}
+		}
+
+		if newMax == maxWeight {
+			return
+		}
+
+		node.maxWeight.Store(newMax)
+		from, to = maxWeight, newMax
+	}
+}
+
+// A word found by FuzzySearch and how many edits away it is
+type ConcurrentFuzzyMatch struct {
+	Word  string
+	Node  *ConcurrentTrieNode
+	Edits int
+}
+
+// FuzzySearch finds up to limit words in the trie within maxEdits
+// Levenshtein distance of word, like Trie.FuzzySearch
+func (t *ConcurrentTrie) FuzzySearch(word string, maxEdits, limit int) []ConcurrentFuzzyMatch {
+	return fuzzySearch(&t.root, word, maxEdits, limit, false, newConcurrentFuzzyMatch)
+}
+
+// DamerauFuzzySearch is like FuzzySearch but also counts swapping two
+// letters next to each other as one edit, like Trie.DamerauFuzzySearch
+func (t *ConcurrentTrie) DamerauFuzzySearch(word string, maxEdits, limit int) []ConcurrentFuzzyMatch {
+	return fuzzySearch(&t.root, word, maxEdits, limit, true, newConcurrentFuzzyMatch)
+}
+
+func newConcurrentFuzzyMatch(word string, node *ConcurrentTrieNode, edits int) ConcurrentFuzzyMatch {
+	return ConcurrentFuzzyMatch{Word: word, Node: node, Edits: edits}
+}
+
+// FindCompletions does a breadth-first search below this node, and finds up
+// to max completed words under it, like Trie.FindCompletions
+func (n *ConcurrentTrieNode) FindCompletions(word string, maxWords int) []ConcurrentCompletion {
+	return findCompletions(n, word, maxWords, newConcurrentCompletion)
+}
+
+// FindTopCompletions finds up to maxWords completed words under this node
+// with the highest weights, best first, like Trie.FindTopCompletions. If
+// weights change during the search, the order may be a little off.
+func (n *ConcurrentTrieNode) FindTopCompletions(word string, maxWords int) []ConcurrentCompletion {
+	return findTopCompletions(n, word, maxWords, newConcurrentCompletion)
+}
+
+// Stats counts the nodes and words in the trie and estimates how much
+// memory they use
+func (t *ConcurrentTrie) Stats() TrieStats {
+	return trieStats(&t.root)
+}
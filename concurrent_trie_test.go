@@ -0,0 +1,212 @@
+package algo_test
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+	"testing"
+
+	"github.com/brnstz/algo"
+)
+
+// Concurrent trie should give the same answers as Trie
+func TestConcurrentTrie(t *testing.T) {
+	trie, _, words := loadTries("data/tale.txt", t)
+	words = uniqueWords(words)
+
+	ct := algo.NewConcurrentTrie()
+	for i, word := range words {
+		ct.Add(word, 1)
+		ct.SetWeight(word, int64(i%50))
+		trie.SetWeight(word, int64(i%50))
+	}
+
+	if ct.Stats().Words != trie.Stats().Words || ct.Stats().Nodes != trie.Stats().Nodes {
+		t.Fatalf("Expected %+v but got %+v", trie.Stats(), ct.Stats())
+	}
+
+	// Remove every third word from both
+	for i, word := range words {
+		if i%3 == 0 {
+			e1, n1 := trie.Remove(word)
+			e2, n2 := ct.Remove(word)
+			if e1 != e2 || n1 != n2 {
+				t.Fatalf("Removing %q: %v, %v but concurrent: %v, %v", word, e1, n1, e2, n2)
+			}
+		}
+	}
+
+	// Lower some weights in both, so the highest weights have to be found
+	// again
+	for i, word := range words {
+		if i%7 == 1 {
+			trie.SetWeight(word, 0)
+			ct.SetWeight(word, 0)
+		}
+	}
+
+	for _, query := range []string{"teh", "wrost", "tiems", "xyzzy"} {
+		var expected, actual []string
+		for _, m := range trie.DamerauFuzzySearch(query, 2, 50) {
+			expected = append(expected, fmt.Sprint(m.Word, m.Edits))
+		}
+		for _, m := range ct.DamerauFuzzySearch(query, 2, 50) {
+			actual = append(actual, fmt.Sprint(m.Word, m.Edits))
+		}
+		if fmt.Sprint(expected) != fmt.Sprint(actual) {
+			t.Fatalf("%q fuzzy matches: %v but concurrent: %v", query, expected, actual)
+		}
+
+		if len(trie.FuzzySearch(query, 1, 50)) != len(ct.FuzzySearch(query, 1, 50)) {
+			t.Fatalf("%q fuzzy matches without swaps don't match", query)
+		}
+	}
+
+	for _, prefix := range []string{"", "t", "th", "be", "wor", "qq"} {
+		exists, node := trie.Exists(prefix)
+		cExists, cNode := ct.Exists(prefix)
+
+		if exists != cExists || (node == nil) != (cNode == nil) {
+			t.Fatalf("%q exists: %v but concurrent: %v", prefix, exists, cExists)
+		}
+		if node == nil {
+			continue
+		}
+
+		var expected, actual []string
+		for _, c := range node.FindCompletions(prefix, 20) {
+			expected = append(expected, c.Word)
+		}
+		for _, c := range cNode.FindCompletions(prefix, 20) {
+			actual = append(actual, c.Word)
+		}
+		if fmt.Sprint(expected) != fmt.Sprint(actual) {
+			t.Fatalf("%q completions: %v but concurrent: %v", prefix, expected, actual)
+		}
+
+		expected, actual = nil, nil
+		for _, c := range node.FindTopCompletions(prefix, 20) {
+			expected = append(expected, fmt.Sprint(c.Node.Weight))
+		}
+		for _, c := range cNode.FindTopCompletions(prefix, 20) {
+			actual = append(actual, fmt.Sprint(c.Node.Weight()))
+		}
+		if fmt.Sprint(expected) != fmt.Sprint(actual) {
+			t.Fatalf("%q top completions: %v but concurrent: %v", prefix, expected, actual)
+		}
+	}
+
+	if exists, _ := ct.ClearBits("qqqq", 1); exists {
+		t.Fatal("Cleared bits of missing word")
+	}
+}
+
+// Run this with -race to make sure readers and writers can share a trie
+func TestConcurrentTrieReadersAndWriters(t *testing.T) {
+	_, _, words := loadTries("data/tale.txt", t)
+	words = uniqueWords(words)
+
+	numWriters := 4
+	numReaders := 4
+
+	ct := algo.NewConcurrentTrie()
+	done := make(chan struct{})
+
+	var writers, readers sync.WaitGroup
+
+	// Each writer adds its share of the words with its own bit, then
+	// clears the bit on half of them
+	for w := 0; w < numWriters; w++ {
+		writers.Add(1)
+		go func(w int) {
+			defer writers.Done()
+
+			bit := int64(1) << w
+			for i := w; i < len(words); i += numWriters {
+				ct.Add(words[i], bit)
+				ct.SetWeight(words[i], int64(i))
+			}
+			for i := w; i < len(words); i += numWriters * 2 {
+				ct.ClearBits(words[i], bit)
+			}
+		}(w)
+	}
+
+	for r := 0; r < numReaders; r++ {
+		readers.Add(1)
+		go func(r int) {
+			defer readers.Done()
+
+			for i := r; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				word := words[i%len(words)]
+				prefix := word[:len(word)/2]
+
+				_, node := ct.Exists(prefix)
+				if node == nil {
+					continue
+				}
+
+				for _, c := range node.FindCompletions(prefix, 10) {
+					if c.Word[:len(prefix)] != prefix {
+						t.Errorf("%q doesn't start with %q", c.Word, prefix)
+					}
+				}
+				node.FindTopCompletions(prefix, 10)
+			}
+		}(r)
+	}
+
+	writers.Wait()
+	close(done)
+	readers.Wait()
+
+	// Every other word for each writer should be left
+	for i, word := range words {
+		exists, _ := ct.Exists(word)
+		if expected := (i/numWriters)%2 == 1; exists != expected {
+			t.Fatalf("Expected exists to be %v for %q", expected, word)
+		}
+	}
+}
+
+// Writers that set the weight from the value shouldn't lose each other's
+// updates
+func TestConcurrentTrieSetWeightFunc(t *testing.T) {
+	ct := algo.NewConcurrentTrie()
+
+	popcount := func(value int64) int64 {
+		return int64(bits.OnesCount64(uint64(value)))
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 500; i++ {
+				word := fmt.Sprint("word", i)
+				ct.Add(word, 1<<w)
+				ct.SetWeightFunc(word, popcount)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 500; i++ {
+		_, node := ct.Exists(fmt.Sprint("word", i))
+		if node.Weight() != 8 {
+			t.Fatalf("Expected weight 8 but got %v", node.Weight())
+		}
+	}
+
+	if ct.SetWeightFunc("qqqq", popcount) {
+		t.Fatal("Set weight of missing word")
+	}
+}
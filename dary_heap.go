@@ -0,0 +1,176 @@
+package algo
+
+import (
+	"cmp"
+)
+
+// DaryHeap is an array-backed min heap where each node has up to d
+// children. A larger d makes for a shallower tree, so Insert and
+// DecreaseKey get cheaper while ExtractMin compares more children per level.
+type DaryHeap[K cmp.Ordered, V any] struct {
+	// Number of children per node
+	d int
+
+	// Our items in heap order, starting at items[0]
+	items []*HeapItem[K, V]
+
+	// Which heap our items are on
+	owner *heapOwner
+}
+
+// Create a new d-ary heap. Any d less than 2 is treated as 2.
+func NewDaryHeap[K cmp.Ordered, V any](d int) *DaryHeap[K, V] {
+	if d < 2 {
+		d = 2
+	}
+
+	return &DaryHeap[K, V]{d: d}
+}
+
+// Insert value with key
+func (h *DaryHeap[K, V]) Insert(key K, value V) *HeapItem[K, V] {
+	item := &HeapItem[K, V]{
+		key:    key,
+		value:  value,
+		queued: true,
+		owner:  ensureOwner(&h.owner),
+		index:  len(h.items),
+	}
+
+	// Store the item at the end and swim it up into place
+	h.items = append(h.items, item)
+	h.swim(item.index)
+
+	return item
+}
+
+// Min returns the item with the lowest key without deleting it
+func (h *DaryHeap[K, V]) Min() (*HeapItem[K, V], error) {
+	if h.IsEmpty() {
+		return nil, PQEmpty
+	}
+
+	return h.items[0], nil
+}
+
+// ExtractMin deletes the item with the lowest key and returns it
+func (h *DaryHeap[K, V]) ExtractMin() (*HeapItem[K, V], error) {
+	if h.IsEmpty() {
+		return nil, PQEmpty
+	}
+
+	min := h.items[0]
+	last := len(h.items) - 1
+
+	// Put the last item on top, drop the old min off the end and restore
+	// heap order
+	h.swap(0, last)
+	h.items[last] = nil
+	h.items = h.items[:last]
+	h.sink(0)
+
+	min.queued = false
+
+	return min, nil
+}
+
+// DecreaseKey lowers the key of item
+func (h *DaryHeap[K, V]) DecreaseKey(item *HeapItem[K, V], key K) error {
+	err := item.checkDecrease(h.owner, key)
+	if err != nil {
+		return err
+	}
+
+	// A lower key can only move up the heap
+	item.key = key
+	h.swim(item.index)
+
+	return nil
+}
+
+// Merge moves every item from other onto this heap. This takes O(n) time
+// since we rebuild the heap from scratch.
+func (h *DaryHeap[K, V]) Merge(other MinHeap[K, V]) error {
+	o, ok := other.(*DaryHeap[K, V])
+	if !ok {
+		return HeapMismatch
+	}
+
+	if o == h {
+		return nil
+	}
+
+	mergeOwner(&h.owner, &o.owner)
+
+	for _, item := range o.items {
+		item.index = len(h.items)
+		h.items = append(h.items, item)
+	}
+	o.items = nil
+
+	// Sink every node that has children, from the bottom up
+	for i := (len(h.items) - 2) / h.d; i >= 0; i-- {
+		h.sink(i)
+	}
+
+	return nil
+}
+
+// What is the current number of items on the heap?
+func (h *DaryHeap[K, V]) Size() int {
+	return len(h.items)
+}
+
+// Is our heap empty?
+func (h *DaryHeap[K, V]) IsEmpty() bool {
+	return len(h.items) < 1
+}
+
+// Starting from k, "swim" lower keys up toward index 0
+func (h *DaryHeap[K, V]) swim(k int) {
+	for k > 0 {
+		parent := (k - 1) / h.d
+
+		if h.items[parent].key <= h.items[k].key {
+			break
+		}
+
+		h.swap(parent, k)
+		k = parent
+	}
+}
+
+// Starting with k, "sink" higher keys down the tree
+func (h *DaryHeap[K, V]) sink(k int) {
+	n := len(h.items)
+
+	for {
+		// Find the smallest of our children, if any
+		first := h.d*k + 1
+		if first >= n {
+			break
+		}
+
+		j := first
+		for c := first + 1; c < first+h.d && c < n; c++ {
+			if h.items[c].key < h.items[j].key {
+				j = c
+			}
+		}
+
+		// If the parent is already no bigger than the child, we're done
+		if h.items[k].key <= h.items[j].key {
+			break
+		}
+
+		h.swap(k, j)
+		k = j
+	}
+}
+
+// Swap items at index i and j
+func (h *DaryHeap[K, V]) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
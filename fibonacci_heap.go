@@ -0,0 +1,289 @@
+package algo
+
+import (
+	"cmp"
+)
+
+// FibonacciHeap is a collection of heap-ordered trees kept in a circular
+// root list. Insert, Merge and DecreaseKey take O(1) amortized time and
+// ExtractMin takes O(log n) amortized time, which gives Dijkstra's
+// algorithm its best bound of O(E + V log V).
+type FibonacciHeap[K cmp.Ordered, V any] struct {
+	// The root with the lowest key. It is also our entry point into the
+	// root list.
+	min *HeapItem[K, V]
+
+	n int
+
+	// Which heap our items are on
+	owner *heapOwner
+}
+
+// Siblings at every level are kept in a circular, doubly linked list via
+// left and right. Each node points to its parent and one of its children.
+
+// Create a new, empty Fibonacci heap
+func NewFibonacciHeap[K cmp.Ordered, V any]() *FibonacciHeap[K, V] {
+	return &FibonacciHeap[K, V]{}
+}
+
+// Insert value with key
+func (h *FibonacciHeap[K, V]) Insert(key K, value V) *HeapItem[K, V] {
+	item := &HeapItem[K, V]{
+		key:    key,
+		value:  value,
+		queued: true,
+		owner:  ensureOwner(&h.owner),
+	}
+
+	h.addRoot(item)
+	h.n++
+
+	return item
+}
+
+// Min returns the item with the lowest key without deleting it
+func (h *FibonacciHeap[K, V]) Min() (*HeapItem[K, V], error) {
+	if h.IsEmpty() {
+		return nil, PQEmpty
+	}
+
+	return h.min, nil
+}
+
+// ExtractMin deletes the item with the lowest key and returns it
+func (h *FibonacciHeap[K, V]) ExtractMin() (*HeapItem[K, V], error) {
+	if h.IsEmpty() {
+		return nil, PQEmpty
+	}
+
+	min := h.min
+
+	// Move every child of min up to the root list
+	for min.child != nil {
+		child := min.child
+		h.removeChild(min, child)
+		h.addRoot(child)
+	}
+
+	// Remove min from the root list
+	if min.right == min {
+		h.min = nil
+	} else {
+		min.left.right = min.right
+		min.right.left = min.left
+		h.min = min.right
+		h.consolidate()
+	}
+
+	h.n--
+
+	min.left = nil
+	min.right = nil
+	min.queued = false
+
+	return min, nil
+}
+
+// DecreaseKey lowers the key of item
+func (h *FibonacciHeap[K, V]) DecreaseKey(item *HeapItem[K, V], key K) error {
+	err := item.checkDecrease(h.owner, key)
+	if err != nil {
+		return err
+	}
+
+	item.key = key
+
+	// If we now violate heap order, cut item out to the root list
+	parent := item.parent
+	if parent != nil && item.key < parent.key {
+		h.cut(item, parent)
+		h.cascadingCut(parent)
+	}
+
+	if item.key < h.min.key {
+		h.min = item
+	}
+
+	return nil
+}
+
+// Merge moves every item from other onto this heap in O(1) time
+func (h *FibonacciHeap[K, V]) Merge(other MinHeap[K, V]) error {
+	o, ok := other.(*FibonacciHeap[K, V])
+	if !ok {
+		return HeapMismatch
+	}
+
+	if o == h || o.min == nil {
+		return nil
+	}
+
+	mergeOwner(&h.owner, &o.owner)
+
+	if h.min == nil {
+		h.min = o.min
+	} else {
+		// Splice the two circular root lists together
+		hRight := h.min.right
+		oLeft := o.min.left
+
+		h.min.right = o.min
+		o.min.left = h.min
+		oLeft.right = hRight
+		hRight.left = oLeft
+
+		if o.min.key < h.min.key {
+			h.min = o.min
+		}
+	}
+
+	h.n += o.n
+
+	o.min = nil
+	o.n = 0
+
+	return nil
+}
+
+// What is the current number of items on the heap?
+func (h *FibonacciHeap[K, V]) Size() int {
+	return h.n
+}
+
+// Is our heap empty?
+func (h *FibonacciHeap[K, V]) IsEmpty() bool {
+	return h.n < 1
+}
+
+// Add item to the root list, updating min if needed
+func (h *FibonacciHeap[K, V]) addRoot(item *HeapItem[K, V]) {
+	item.parent = nil
+
+	if h.min == nil {
+		item.left = item
+		item.right = item
+		h.min = item
+		return
+	}
+
+	// Insert just to the right of min
+	item.left = h.min
+	item.right = h.min.right
+	h.min.right.left = item
+	h.min.right = item
+
+	if item.key < h.min.key {
+		h.min = item
+	}
+}
+
+// Remove child from the child list of parent
+func (h *FibonacciHeap[K, V]) removeChild(parent, child *HeapItem[K, V]) {
+	if child.right == child {
+		parent.child = nil
+	} else {
+		child.left.right = child.right
+		child.right.left = child.left
+
+		if parent.child == child {
+			parent.child = child.right
+		}
+	}
+
+	parent.degree--
+
+	child.parent = nil
+	child.left = child
+	child.right = child
+}
+
+// Make child a child of parent
+func (h *FibonacciHeap[K, V]) link(parent, child *HeapItem[K, V]) {
+	child.parent = parent
+	child.mark = false
+
+	if parent.child == nil {
+		child.left = child
+		child.right = child
+		parent.child = child
+	} else {
+		child.left = parent.child
+		child.right = parent.child.right
+		parent.child.right.left = child
+		parent.child.right = child
+	}
+
+	parent.degree++
+}
+
+// Combine roots of the same degree until every root has a distinct degree
+func (h *FibonacciHeap[K, V]) consolidate() {
+	var (
+		roots    []*HeapItem[K, V]
+		byDegree []*HeapItem[K, V]
+	)
+
+	// Collect the roots first, since linking changes the list
+	item := h.min
+	for {
+		roots = append(roots, item)
+		item = item.right
+
+		if item == h.min {
+			break
+		}
+	}
+
+	for _, x := range roots {
+		d := x.degree
+
+		// While another root has our degree, link the larger key under
+		// the smaller one
+		for d < len(byDegree) && byDegree[d] != nil {
+			y := byDegree[d]
+			if y.key < x.key {
+				x, y = y, x
+			}
+
+			h.link(x, y)
+			byDegree[d] = nil
+			d++
+		}
+
+		for d >= len(byDegree) {
+			byDegree = append(byDegree, nil)
+		}
+		byDegree[d] = x
+	}
+
+	// Rebuild the root list from what's left
+	h.min = nil
+	for _, x := range byDegree {
+		if x != nil {
+			h.addRoot(x)
+		}
+	}
+}
+
+// Cut item from parent and move it to the root list
+func (h *FibonacciHeap[K, V]) cut(item, parent *HeapItem[K, V]) {
+	h.removeChild(parent, item)
+	h.addRoot(item)
+	item.mark = false
+}
+
+// Cut item from its parent if it has already lost a child, continuing up
+// the tree
+func (h *FibonacciHeap[K, V]) cascadingCut(item *HeapItem[K, V]) {
+	for item.parent != nil {
+		if !item.mark {
+			item.mark = true
+			return
+		}
+
+		parent := item.parent
+		h.cut(item, parent)
+		item = parent
+	}
+}
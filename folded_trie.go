@@ -0,0 +1,170 @@
+package algo
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Fold returns s without case or accents, so that words which only differ
+// in those ways fold to the same string. Accents are stripped from Latin,
+// Greek and Cyrillic letters, whether they are precomposed or written with
+// combining marks, using the subset of NFD in foldAccents. Letters are then
+// case folded with Unicode simple case folding, plus the full case folding
+// of ß and the Latin ligatures, so "STRASSE" matches "Straße". Other full
+// case foldings, like the Armenian ligatures, are left out.
+func Fold(s string) string {
+	var b strings.Builder
+
+	b.Grow(len(s))
+
+	for _, r := range s {
+		// Drop combining diacritical marks
+		if r >= 0x300 && r <= 0x36f {
+			continue
+		}
+
+		if base, ok := foldAccents[r]; ok {
+			r = base
+		}
+
+		if full, ok := foldFull[r]; ok {
+			b.WriteString(full)
+			continue
+		}
+
+		b.WriteRune(foldCase(r))
+	}
+
+	return b.String()
+}
+
+// Letters whose case folding is more than one letter
+var foldFull = map[rune]string{
+	'ß': "ss", 'ẞ': "ss",
+	'ﬀ': "ff", 'ﬁ': "fi", 'ﬂ': "fl", 'ﬃ': "ffi", 'ﬄ': "ffl", 'ﬅ': "st", 'ﬆ': "st",
+}
+
+// foldCase returns the same rune for every case of r. SimpleFold goes around
+// all the runes that are the same letter as r. We lowercase each one and
+// keep the lowest, so every rune in the loop gives the same answer.
+func foldCase(r rune) rune {
+	folded := unicode.ToLower(unicode.ToUpper(r))
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		folded = min(folded, unicode.ToLower(unicode.ToUpper(f)))
+	}
+
+	return folded
+}
+
+// FoldedTrie is a Trie that ignores case and accents. Words are run through
+// Fold before they are added or looked up, but completions come back
+// spelled the way they were first added.
+type FoldedTrie struct {
+	trie *Trie
+
+	// The original spelling of each word node
+	spellings map[*Trie]string
+}
+
+// NewFoldedTrie creates a new, empty folded trie
+func NewFoldedTrie() *FoldedTrie {
+	return &FoldedTrie{
+		trie:      NewTrie(),
+		spellings: map[*Trie]string{},
+	}
+}
+
+// Add a word to the trie. Returns how many new nodes were created, and the
+// maximum number of siblings a node has.
+func (ft *FoldedTrie) Add(word string, value int64) (int, int) {
+	key := Fold(word)
+
+	newNodes, maxSiblings := ft.trie.Add(key, value)
+
+	_, node := ft.trie.Exists(key)
+	if _, ok := ft.spellings[node]; !ok && node.Value > 0 {
+		ft.spellings[node] = word
+	}
+
+	return newNodes, maxSiblings
+}
+
+// Exists returns a boolean indicating whether a word that folds the same as
+// this one is in the trie. It also returns the Trie node when found.
+func (ft *FoldedTrie) Exists(word string) (bool, *Trie) {
+	return ft.trie.Exists(Fold(word))
+}
+
+// Spelling returns how the word at node was first spelled when it was added
+func (ft *FoldedTrie) Spelling(node *Trie) string {
+	return ft.spellings[node]
+}
+
+// Remove a word that folds the same as this one from the trie. Returns
+// whether the word existed and how many nodes were removed.
+func (ft *FoldedTrie) Remove(word string) (bool, int) {
+	_, node := ft.Exists(word)
+
+	exists, removed := ft.trie.Remove(Fold(word))
+	if exists {
+		delete(ft.spellings, node)
+	}
+
+	return exists, removed
+}
+
+// ClearBits turns off bits in the value of a word that folds the same as
+// this one. If no bits are left, the word is removed. Returns whether the
+// word existed and how many nodes were removed.
+func (ft *FoldedTrie) ClearBits(word string, bits int64) (bool, int) {
+	_, node := ft.Exists(word)
+
+	exists, removed := ft.trie.ClearBits(Fold(word), bits)
+	if exists && node.Value == 0 {
+		delete(ft.spellings, node)
+	}
+
+	return exists, removed
+}
+
+// SetWeight sets the weight of a word that is already in the trie. Returns
+// whether the word existed.
+func (ft *FoldedTrie) SetWeight(word string, weight int64) bool {
+	return ft.trie.SetWeight(Fold(word), weight)
+}
+
+// FindCompletions finds up to maxWords words that start with word, like
+// Trie.FindCompletions, using their original spelling
+func (ft *FoldedTrie) FindCompletions(word string, maxWords int) []Completion {
+	key := Fold(word)
+
+	_, node := ft.trie.Exists(key)
+	if node == nil {
+		return nil
+	}
+
+	return ft.respell(node.FindCompletions(key, maxWords))
+}
+
+// FindTopCompletions finds up to maxWords words that start with word with
+// the highest weights, like Trie.FindTopCompletions, using their original
+// spelling
+func (ft *FoldedTrie) FindTopCompletions(word string, maxWords int) []Completion {
+	key := Fold(word)
+
+	_, node := ft.trie.Exists(key)
+	if node == nil {
+		return nil
+	}
+
+	return ft.respell(node.FindTopCompletions(key, maxWords))
+}
+
+// Replace the folded words in completions with their original spelling
+func (ft *FoldedTrie) respell(completions []Completion) []Completion {
+	for i := range completions {
+		completions[i].Word = ft.spellings[completions[i].Node]
+	}
+
+	return completions
+}
@@ -0,0 +1,102 @@
+package algo_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/brnstz/algo"
+)
+
+func TestFold(t *testing.T) {
+	tests := map[string]string{
+		"Paris":         "paris",
+		"PARIS":         "paris",
+		"Émile Zola":    "emile zola",
+		"E\u0301mile":   "emile",
+		"Ångström":      "angstrom",
+		"São Paulo":     "sao paulo",
+		"Straße":        "strasse",
+		"STRASSE":       "strasse",
+		"Oﬃce":          "office",
+		"ΣΊΣΥΦΟΣ":       "σισυφοσ",
+		"σίσυφος":       "σισυφοσ",
+		"Ꮳ":             "ꮳ",
+		"ꮳ":             "ꮳ",
+		"Ἀθῆναι":        "αθηναι",
+		"Ёлка":          "елка",
+		"ſ":             "s",
+		"がっこう":          "がっこう",
+		"東京":            "東京",
+		"Nguyễn Du":     "nguyen du",
+		"Łódź":          "łodz",
+		"Kelvin \u212a": "kelvin k",
+	}
+
+	for s, expected := range tests {
+		if actual := algo.Fold(s); actual != expected {
+			t.Fatalf("Expected %q to fold to %q but got %q", s, expected, actual)
+		}
+	}
+}
+
+func TestFoldedTrie(t *testing.T) {
+	trie := algo.NewFoldedTrie()
+
+	trie.Add("Paris", 1)
+	trie.Add("paris", 2)
+	trie.Add("Pärchen", 1)
+	trie.Add("París", 4)
+	trie.Add("Pâtisserie", 1)
+
+	exists, node := trie.Exists("paris")
+	if !exists || node.Value != 1|2|4 {
+		t.Fatalf("Expected paris to exist with value 7 but got %v, %v", exists, node)
+	}
+	if trie.Spelling(node) != "Paris" {
+		t.Fatalf("Expected the first spelling but got %q", trie.Spelling(node))
+	}
+
+	for _, word := range []string{"PARIS", "pArIs", "París"} {
+		if exists, _ := trie.Exists(word); !exists {
+			t.Fatalf("Can't find %q", word)
+		}
+	}
+
+	var words []string
+	for _, c := range trie.FindCompletions("PA", 10) {
+		words = append(words, c.Word)
+	}
+	if fmt.Sprint(words) != "[Paris Pärchen Pâtisserie]" {
+		t.Fatalf("Unexpected completions %v", words)
+	}
+
+	trie.SetWeight("patisserie", 10)
+	top := trie.FindTopCompletions("pa", 1)
+	if len(top) != 1 || top[0].Word != "Pâtisserie" {
+		t.Fatalf("Unexpected top completions %v", top)
+	}
+
+	if len(trie.FindCompletions("xyz", 10)) != 0 {
+		t.Fatal("Found completions for missing prefix")
+	}
+
+	// Once it's gone, a new spelling takes over
+	if exists, _ := trie.Remove("PARÍS"); !exists {
+		t.Fatal("Can't remove paris")
+	}
+	if exists, _ := trie.Exists("Paris"); exists {
+		t.Fatal("Paris still exists")
+	}
+	trie.Add("PARIS", 1)
+	_, node = trie.Exists("paris")
+	if trie.Spelling(node) != "PARIS" {
+		t.Fatalf("Expected the new spelling but got %q", trie.Spelling(node))
+	}
+
+	if exists, _ := trie.ClearBits("parchen", 1); !exists {
+		t.Fatal("Can't clear bits")
+	}
+	if exists, _ := trie.Exists("pärchen"); exists {
+		t.Fatal("Pärchen still exists")
+	}
+}
@@ -36,12 +36,6 @@ func (e *Edge) String() string {
 	return fmt.Sprintf("%v => %v @ %v", e.From.Value, e.To.Value, e.Weight)
 }
 
-// PQLess implements the PQItem to allow us to use edges on a PriorityQueue
-func (e *Edge) PQLess(other PQItem) bool {
-	otherEdge := other.(*Edge)
-	return e.Weight > otherEdge.Weight
-}
-
 // Path defines a way to get from Edges[0].From to Edges[len(Edges)-1].To
 // along with the Weight (or cost / distance, etc.) of going there.
 type Path struct {
@@ -89,27 +83,16 @@ func (g *Graph) AddEdge(edge *Edge) {
 
 // MinimumSpanningTree finds the minimal list of edges to span the entire
 // graph that is connected to v. If v == nil, we use the first Edge.From
-// value.
+// value. Edges are treated as undirected. This is the eager version of
+// Prim's algorithm, which runs in O(E log V) time.
 func (g *Graph) MinimumSpanningTree(v *Vertex) ([]*Edge, error) {
 	var (
-		mst        []*Edge
-		edge       *Edge
-		goodEdge   *Edge
-		edgePQ     *PriorityQueue
-		nextEdgePQ *PriorityQueue
-		err        error
-		pqItem     PQItem
+		mst    []*Edge
+		edge   *Edge
+		vertex *Vertex
+		err    error
 	)
 
-	// Mark which vertices we have visited
-	marked := map[*Vertex]bool{}
-
-	// Initialize edgePQ with all edges
-	edgePQ = NewPriorityQueue(len(g.Edges))
-	for _, edge := range g.Edges {
-		edgePQ.Insert(edge)
-	}
-
 	// If no vertex passed in, then assume the first one
 	if v == nil {
 		if len(g.Edges) > 0 {
@@ -123,162 +106,230 @@ func (g *Graph) MinimumSpanningTree(v *Vertex) ([]*Edge, error) {
 		}
 	}
 
-	// Mark the first vertex as visited
-	marked[v] = true
+	// Map each vertex to every edge that touches it, regardless of
+	// direction
+	incident := map[*Vertex][]*Edge{}
+	for _, edge = range g.Edges {
+		incident[edge.From] = append(incident[edge.From], edge)
+		incident[edge.To] = append(incident[edge.To], edge)
+	}
+
+	// Mark which vertices are in our tree
+	marked := map[*Vertex]bool{}
+
+	// The lowest weight edge we've seen that connects each vertex to
+	// the tree
+	edgeTo := map[*Vertex]*Edge{}
 
-	for !edgePQ.IsEmpty() {
-		// Create a new PQ for the next Loop
-		nextEdgePQ = NewPriorityQueue(len(g.Edges))
+	// Vertices not yet in the tree, keyed by the weight of edgeTo
+	vertexPQ := NewIndexMinPQ[*Vertex, float64]()
 
-		// Find one goodEdge per loop below
-		goodEdge = nil
+	// visit adds vertex to the tree and updates the best edge to each of
+	// its neighbors that isn't already in the tree
+	visit := func(vertex *Vertex) error {
+		marked[vertex] = true
 
-		for !edgePQ.IsEmpty() {
+		for _, edge := range incident[vertex] {
 
-			// Get the lowest weight edge on the priority queue
-			pqItem, err = edgePQ.DelMax()
-			edge = pqItem.(*Edge)
-			if err != nil {
-				return nil, err
+			// Find the other end of this edge
+			other := edge.To
+			if other == vertex {
+				other = edge.From
 			}
 
-			if goodEdge == nil && marked[edge.From] != marked[edge.To] {
-				// Find the lowest weight edge that expands our tree.  That is,
-				// one vertex is marked but the other isn't.
-				goodEdge = edge
+			// Ignore vertices already in the tree
+			if marked[other] {
+				continue
+			}
 
-			} else {
+			if !vertexPQ.Contains(other) {
 
-				// Otherwise, add it to the next queue
-				nextEdgePQ.Insert(edge)
-			}
-		}
+				// First time we've seen this vertex
+				edgeTo[other] = edge
+				err := vertexPQ.Insert(other, edge.Weight)
+				if err != nil {
+					return err
+				}
 
-		// If we didn't find a goodEdge, then we're done
-		if goodEdge == nil {
-			break
-		}
+			} else if edge.Weight < edgeTo[other].Weight {
 
-		// Save that each vertex for this Edge is marked
-		marked[goodEdge.From] = true
-		marked[goodEdge.To] = true
+				// We've found a cheaper way to connect this vertex
+				edgeTo[other] = edge
+				err := vertexPQ.DecreaseKey(other, edge.Weight)
+				if err != nil {
+					return err
+				}
+			}
+		}
 
-		// Add it to the list of edges for the solution
-		mst = append(mst, goodEdge)
+		return nil
+	}
 
-		// Use the new queue for next round
-		edgePQ = nextEdgePQ
+	err = visit(v)
+	if err != nil {
+		return nil, err
 	}
 
-	return mst, nil
-}
+	// Add the closest vertex to the tree until there are none left
+	for !vertexPQ.IsEmpty() {
+		vertex, _, err = vertexPQ.DelMin()
+		if err != nil {
+			return nil, err
+		}
 
-type vertexWeight struct {
-	vertex *Vertex
-	weight float64
-}
+		// Add its edge to the list of edges for the solution
+		mst = append(mst, edgeTo[vertex])
 
-// PQLess implements the PQItem to allow us to use edges on a PriorityQueue
-func (vw *vertexWeight) PQLess(other PQItem) bool {
-	otherVW := other.(*vertexWeight)
-	return vw.weight > otherVW.weight
-}
+		err = visit(vertex)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-func (vw *vertexWeight) String() string {
-	return fmt.Sprintf("%v", vw.weight)
+	return mst, nil
 }
 
 // ShortestPath returns a mapping of the shortest path from source to every
-// connected vertex in the Graph
+// connected vertex in the Graph. This is Dijkstra's algorithm, which runs in
+// O(E log V) time.
 func (g *Graph) ShortestPath(source *Vertex) (map[*Vertex]*Path, error) {
 	var (
 		err    error
 		weight float64
-		i      int
 
-		item   PQItem
-		vw     *vertexWeight
 		vertex *Vertex
-		next   *Vertex
 		edge   *Edge
 	)
 
-	visited := map[*Vertex]bool{}
 	edgeTo := map[*Vertex]*Edge{}
-	weightTo := map[*Vertex]*vertexWeight{}
-	paths := map[*Vertex]*Path{}
-
-	vwPQ := NewPriorityQueue(len(g.Adj))
+	weightTo := map[*Vertex]float64{}
 
+	// Every vertex starts out infinitely far away, except the source
 	for vertex = range g.Adj {
+		weightTo[vertex] = math.MaxFloat64
+	}
+	weightTo[source] = 0.0
+
+	// Vertices we've reached but not yet processed, keyed by their
+	// current weight
+	vertexPQ := NewIndexMinPQ[*Vertex, float64]()
+	err = vertexPQ.Insert(source, 0.0)
+	if err != nil {
+		return nil, err
+	}
 
-		if vertex == source {
-			// If this is the same vertex, weight is 0
-			weight = 0.0
-		} else {
-			// Otherwise it's infinite
-			weight = math.MaxFloat64
+	// Process vertices from lowest to highest weight
+	for !vertexPQ.IsEmpty() {
+		vertex, _, err = vertexPQ.DelMin()
+		if err != nil {
+			return nil, err
 		}
 
-		// Create a vertexWeight to put onto the priorityQueue
-		vw := &vertexWeight{
-			vertex: vertex,
-			weight: weight,
+		for _, edge = range g.Adj[vertex] {
+
+			// What is the weight if we use this edge?
+			weight = weightTo[vertex] + edge.Weight
+
+			// If it isn't less than the current weight to edge.To, then
+			// ignore it
+			if weight >= weightTo[edge.To] {
+				continue
+			}
+
+			weightTo[edge.To] = weight
+			edgeTo[edge.To] = edge
+
+			// Register the weight with our priority queue
+			if vertexPQ.Contains(edge.To) {
+				err = vertexPQ.DecreaseKey(edge.To, weight)
+			} else {
+				err = vertexPQ.Insert(edge.To, weight)
+			}
+			if err != nil {
+				return nil, err
+			}
 		}
+	}
+
+	return g.paths(source, edgeTo, weightTo), nil
+}
 
-		weightTo[vertex] = vw
+// ShortestPathWithHeap finds the same paths as ShortestPath, but uses h as
+// the priority queue instead of an IndexMinPQ. This lets us benchmark heap
+// implementations against each other on the same graph. h should be empty.
+func (g *Graph) ShortestPathWithHeap(source *Vertex, h MinHeap[float64, *Vertex]) (map[*Vertex]*Path, error) {
+	var (
+		err    error
+		weight float64
 
-		// Add this vertexWeight to our queue
-		vwPQ.Insert(vw)
+		item *HeapItem[float64, *Vertex]
+		edge *Edge
+	)
+
+	edgeTo := map[*Vertex]*Edge{}
+	weightTo := map[*Vertex]float64{}
+
+	// Our handle on the heap for every vertex we've reached but not yet
+	// processed
+	items := map[*Vertex]*HeapItem[float64, *Vertex]{}
+
+	// Every vertex starts out infinitely far away, except the source
+	for vertex := range g.Adj {
+		weightTo[vertex] = math.MaxFloat64
 	}
+	weightTo[source] = 0.0
 
-	// Process vertices from lowest to highest weight
-	for !vwPQ.IsEmpty() {
+	items[source] = h.Insert(0.0, source)
 
-		// Coerce the pq item into a vertexWeight
-		item, err = vwPQ.DelMax()
+	// Process vertices from lowest to highest weight
+	for !h.IsEmpty() {
+		item, err = h.ExtractMin()
 		if err != nil {
 			return nil, err
 		}
-		vw = item.(*vertexWeight)
-
-		// Ignore vertices we've already visited
-		if visited[vw.vertex] {
-			continue
-		}
+		vertex := item.Value()
+		delete(items, vertex)
 
-		// Mark that we have visited this vertex
-		visited[vw.vertex] = true
+		for _, edge = range g.Adj[vertex] {
 
-		for _, edge = range g.Adj[vw.vertex] {
+			// What is the weight if we use this edge?
+			weight = weightTo[vertex] + edge.Weight
 
-			// Ignore vertices we've already visited
-			if visited[edge.To] {
+			// If it isn't less than the current weight to edge.To, then
+			// ignore it
+			if weight >= weightTo[edge.To] {
 				continue
 			}
 
-			// What is the weight if we use this edge?
-			weight = weightTo[vw.vertex].weight + edge.Weight
-
-			if weight < weightTo[edge.To].weight {
-
-				// If that weight is less that the current weight to
-				// edge.To, then use it.
-				weightTo[edge.To].weight = weight
-				edgeTo[edge.To] = edge
+			weightTo[edge.To] = weight
+			edgeTo[edge.To] = edge
 
-				// Register the weight change with our priority queue
-				i, err = vwPQ.IndexOf(weightTo[edge.To])
+			// Register the weight with our heap
+			if items[edge.To] != nil {
+				err = h.DecreaseKey(items[edge.To], weight)
 				if err != nil {
 					return nil, err
 				}
-				vwPQ.IndicateChange(i)
-
+			} else {
+				items[edge.To] = h.Insert(weight, edge.To)
 			}
 		}
 	}
 
-	// Create a Path object for each vertex that isn't the source
+	return g.paths(source, edgeTo, weightTo), nil
+}
+
+// paths creates a Path object for each vertex that isn't the source, given
+// the last edge and total weight of the shortest path to each vertex
+func (g *Graph) paths(source *Vertex, edgeTo map[*Vertex]*Edge, weightTo map[*Vertex]float64) map[*Vertex]*Path {
+	var (
+		vertex *Vertex
+		next   *Vertex
+		edge   *Edge
+	)
+
+	paths := map[*Vertex]*Path{}
+
 	for vertex = range g.Adj {
 		if vertex == source {
 			continue
@@ -286,7 +337,7 @@ func (g *Graph) ShortestPath(source *Vertex) (map[*Vertex]*Path, error) {
 
 		// Initialize path with the weight and the source/destination
 		path := &Path{
-			Weight: weightTo[vertex].weight,
+			Weight: weightTo[vertex],
 			From:   source,
 			To:     vertex,
 		}
@@ -320,5 +371,5 @@ func (g *Graph) ShortestPath(source *Vertex) (map[*Vertex]*Path, error) {
 		paths[vertex] = path
 	}
 
-	return paths, nil
+	return paths
 }
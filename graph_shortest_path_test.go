@@ -1,6 +1,8 @@
 package algo
 
 import (
+	"fmt"
+	"math/rand"
 	"testing"
 )
 
@@ -70,3 +72,93 @@ func TestShortestPath(t *testing.T) {
 	}
 
 }
+
+// Create a grid shaped graph of size x size vertices, with a randomly
+// weighted edge in each direction between neighbors. This is a rough
+// approximation of a road network.
+func gridGraph(size int) (*Graph, *Vertex) {
+	var newI, newJ int
+
+	r := rand.New(rand.NewSource(1))
+	g := &Graph{}
+
+	vertices := make([][]*Vertex, size)
+	for i := 0; i < size; i++ {
+		vertices[i] = make([]*Vertex, size)
+		for j := 0; j < size; j++ {
+			vertices[i][j] = &Vertex{Value: fmt.Sprintf("(%v,%v)", i, j)}
+		}
+	}
+
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			for _, diff := range [][]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+				newI = i + diff[0]
+				newJ = j + diff[1]
+
+				if newI >= 0 && newI < size && newJ >= 0 && newJ < size {
+					g.AddEdge(
+						&Edge{
+							From:   vertices[i][j],
+							To:     vertices[newI][newJ],
+							Weight: float64(r.Intn(100) + 1),
+						},
+					)
+				}
+			}
+		}
+	}
+
+	return g, vertices[0][0]
+}
+
+// Every heap backend we can run ShortestPathWithHeap on
+var shortestPathHeaps = map[string]func() MinHeap[float64, *Vertex]{
+	"binary":    func() MinHeap[float64, *Vertex] { return NewDaryHeap[float64, *Vertex](2) },
+	"4-ary":     func() MinHeap[float64, *Vertex] { return NewDaryHeap[float64, *Vertex](4) },
+	"pairing":   func() MinHeap[float64, *Vertex] { return NewPairingHeap[float64, *Vertex]() },
+	"fibonacci": func() MinHeap[float64, *Vertex] { return NewFibonacciHeap[float64, *Vertex]() },
+}
+
+// Every heap backend should find the same weights as ShortestPath
+func TestShortestPathWithHeap(t *testing.T) {
+	g, source := gridGraph(30)
+
+	expected, err := g.ShortestPath(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, newHeap := range shortestPathHeaps {
+		paths, err := g.ShortestPathWithHeap(source, newHeap())
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		for vertex, path := range expected {
+			if paths[vertex].Weight != path.Weight {
+				t.Fatalf("%v: expected path of weight %v but got this path: %v", name, path.Weight, paths[vertex])
+			}
+		}
+	}
+}
+
+func BenchmarkShortestPath(b *testing.B) {
+	g, source := gridGraph(100)
+
+	for i := 0; i < b.N; i++ {
+		g.ShortestPath(source)
+	}
+}
+
+func BenchmarkShortestPathWithHeap(b *testing.B) {
+	g, source := gridGraph(100)
+
+	for name, newHeap := range shortestPathHeaps {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				g.ShortestPathWithHeap(source, newHeap())
+			}
+		})
+	}
+}
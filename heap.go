@@ -0,0 +1,129 @@
+package algo
+
+import (
+	"cmp"
+	"errors"
+)
+
+var HeapMismatch = errors.New("cannot merge heaps of different types")
+
+// MinHeap is a min priority queue of values ordered by key. Every
+// implementation can decrease the key of an item it holds and merge in
+// another heap of the same type, which makes them interchangeable backends
+// for algorithms like Dijkstra's.
+type MinHeap[K cmp.Ordered, V any] interface {
+	// Insert value with key, returning a handle that can later be passed
+	// to DecreaseKey
+	Insert(key K, value V) *HeapItem[K, V]
+
+	// Min returns the item with the lowest key without deleting it
+	Min() (*HeapItem[K, V], error)
+
+	// ExtractMin deletes the item with the lowest key and returns it
+	ExtractMin() (*HeapItem[K, V], error)
+
+	// DecreaseKey lowers the key of an item currently on the heap
+	DecreaseKey(item *HeapItem[K, V], key K) error
+
+	// Merge moves every item from other onto this heap, leaving other
+	// empty. Returns HeapMismatch if other is a different type.
+	Merge(other MinHeap[K, V]) error
+
+	// Size is the current number of items on the heap
+	Size() int
+
+	// IsEmpty is true when there are no items on the heap
+	IsEmpty() bool
+}
+
+// HeapItem is a handle to a value on a MinHeap. Each heap implementation
+// only uses the bookkeeping fields it needs.
+type HeapItem[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+
+	// Is this item currently on a heap, and which one?
+	queued bool
+	owner  *heapOwner
+
+	// DaryHeap: our index in the heap's slice
+	index int
+
+	// PairingHeap and FibonacciHeap: links to other items in the tree
+	parent, child, left, right *HeapItem[K, V]
+
+	// FibonacciHeap: number of children and whether we've lost a child
+	// since becoming a child ourselves
+	degree int
+	mark   bool
+}
+
+// Key is the current key of this item
+func (item *HeapItem[K, V]) Key() K {
+	return item.key
+}
+
+// Value is the value this item holds
+func (item *HeapItem[K, V]) Value() V {
+	return item.value
+}
+
+// Private helper to check that item is on the heap that owner belongs to,
+// and that key is strictly lower than its current key, the same as
+// IndexMinPQ.DecreaseKey
+func (item *HeapItem[K, V]) checkDecrease(owner *heapOwner, key K) error {
+	if item == nil || !item.queued || owner == nil || item.owner.find() != owner {
+		return NotFound
+	}
+
+	if key >= item.key {
+		return PQKeyNotDecreased
+	}
+
+	return nil
+}
+
+// heapOwner tells us which heap an item is on. When a heap is merged into
+// another, its owner links to the other one's instead of us updating every
+// item, so Merge can stay O(1). Like UnionFind, we follow the links to find
+// the current owner.
+type heapOwner struct {
+	merged *heapOwner
+}
+
+// find follows merges to the owner of the heap the item is on now
+func (o *heapOwner) find() *heapOwner {
+	root := o
+	for root.merged != nil {
+		root = root.merged
+	}
+
+	// Point everything on the way directly at the root for next time
+	for o != root {
+		next := o.merged
+		o.merged = root
+		o = next
+	}
+
+	return root
+}
+
+// ensureOwner returns the owner of a heap, creating it if needed
+func ensureOwner(owner **heapOwner) *heapOwner {
+	if *owner == nil {
+		*owner = &heapOwner{}
+	}
+
+	return *owner
+}
+
+// mergeOwner links the owner of a heap being merged away to the owner of the
+// heap it's merged into. The emptied heap gets a new owner the next time
+// something is inserted.
+func mergeOwner(into, from **heapOwner) {
+	if *from != nil {
+		(*from).merged = ensureOwner(into)
+	}
+
+	*from = nil
+}
@@ -0,0 +1,149 @@
+package algo_test
+
+import (
+	"github.com/brnstz/algo"
+
+	"math/rand"
+	"testing"
+)
+
+// Every MinHeap implementation we want to test
+var heapConstructors = map[string]func() algo.MinHeap[int, int]{
+	"binary": func() algo.MinHeap[int, int] { return algo.NewDaryHeap[int, int](2) },
+	"4-ary":  func() algo.MinHeap[int, int] { return algo.NewDaryHeap[int, int](4) },
+	"pairing": func() algo.MinHeap[int, int] {
+		return algo.NewPairingHeap[int, int]()
+	},
+	"fibonacci": func() algo.MinHeap[int, int] {
+		return algo.NewFibonacciHeap[int, int]()
+	},
+}
+
+// Test each heap by inserting random keys into two heaps, decreasing some
+// keys, merging them and then extracting everything in order
+func TestMinHeaps(t *testing.T) {
+	numVals := 10000
+
+	for name, newHeap := range heapConstructors {
+		h1 := newHeap()
+		h2 := newHeap()
+
+		var items []*algo.HeapItem[int, int]
+		for i := 0; i < numVals; i++ {
+			key := rand.Intn(numVals)
+
+			if i%2 == 0 {
+				items = append(items, h1.Insert(key, i))
+			} else {
+				items = append(items, h2.Insert(key, i))
+			}
+		}
+
+		// Pull a few items off the first heap so that it has some
+		// structure before we decrease keys
+		for i := 0; i < numVals/10; i++ {
+			_, err := h1.ExtractMin()
+			if err != nil {
+				t.Fatalf("%v: %v", name, err)
+			}
+		}
+
+		// Decrease the key of every third item that's still queued
+		for i := 0; i < numVals; i += 3 {
+			// Even items are on h1, odd items are on h2
+			h := h1
+			if i%2 == 1 {
+				h = h2
+			}
+
+			// Items we've already extracted are NotFound
+			err := h.DecreaseKey(items[i], items[i].Key()-1-rand.Intn(numVals))
+			if err != nil && err != algo.NotFound {
+				t.Fatalf("%v: %v", name, err)
+			}
+		}
+
+		err := h1.DecreaseKey(items[0], items[0].Key()+1)
+		if err != algo.PQKeyNotDecreased && err != algo.NotFound {
+			t.Fatalf("%v: expected key not decreased error but got %v", name, err)
+		}
+
+		// Keys must go strictly down
+		err = h2.DecreaseKey(items[1], items[1].Key())
+		if err != algo.PQKeyNotDecreased {
+			t.Fatalf("%v: expected key not decreased error but got %v", name, err)
+		}
+
+		// An item on one heap can't be decreased through another
+		err = h1.DecreaseKey(items[1], items[1].Key()-1)
+		if err != algo.NotFound {
+			t.Fatalf("%v: expected not found error but got %v", name, err)
+		}
+
+		expectedSize := h1.Size() + h2.Size()
+
+		err = h1.Merge(h2)
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		if !h2.IsEmpty() {
+			t.Fatalf("%v: expected merged heap to be empty", name)
+		}
+
+		// Once merged, items from h2 belong to h1, but new ones on h2
+		// don't
+		err = h1.DecreaseKey(items[1], items[1].Key()-1)
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		item := h2.Insert(0, -1)
+		if err = h1.DecreaseKey(item, -1); err != algo.NotFound {
+			t.Fatalf("%v: expected not found error but got %v", name, err)
+		}
+		if _, err = h2.ExtractMin(); err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		if h1.Size() != expectedSize {
+			t.Fatalf("%v: expected %v items but found %v", name, expectedSize, h1.Size())
+		}
+
+		lastKey := -numVals * 2
+		for !h1.IsEmpty() {
+			min, err := h1.Min()
+			if err != nil {
+				t.Fatalf("%v: %v", name, err)
+			}
+
+			item, err := h1.ExtractMin()
+			if err != nil {
+				t.Fatalf("%v: %v", name, err)
+			}
+
+			if item != min {
+				t.Fatalf("%v: expected ExtractMin to return Min", name)
+			}
+
+			if item.Key() < lastKey {
+				t.Fatalf("%v: expected ordering from lowest to highest key, but found sequence %v, %v", name, lastKey, item.Key())
+			}
+			lastKey = item.Key()
+		}
+
+		if _, err = h1.ExtractMin(); err != algo.PQEmpty {
+			t.Fatalf("%v: expected empty heap", name)
+		}
+	}
+}
+
+// Test that heaps refuse to merge with a different implementation
+func TestMinHeapMismatch(t *testing.T) {
+	h1 := algo.NewPairingHeap[int, int]()
+	h2 := algo.NewFibonacciHeap[int, int]()
+
+	if h1.Merge(h2) != algo.HeapMismatch {
+		t.Fatal("Expected heap mismatch error")
+	}
+}
@@ -48,12 +48,10 @@ type node struct {
 	right *node
 }
 
-// PQLess for a node considers the item with larger frequency to have less
+// nodeLess considers the node with larger frequency to have less
 // priority. We want to pop them off from least frequest to most.
-func (n *node) PQLess(other algo.PQItem) bool {
-	otherN := other.(*node)
-
-	return n.freq >= otherN.freq
+func nodeLess(a, b *node) bool {
+	return a.freq >= b.freq
 }
 
 // Coder is a Huffman encoder/decoder
@@ -258,12 +256,11 @@ func (c Coder) createFreq(r io.Reader) (map[interface{}]int, error) {
 // createTree accepts the frequency count and builds our Huffman tree
 func (c Coder) createTree(freqs map[interface{}]int) (*node, error) {
 	var (
-		pqItem         algo.PQItem
 		parent, n1, n2 *node
 		err            error
 	)
 
-	pq := algo.NewPriorityQueue(len(freqs))
+	pq := algo.NewUnboundedPriorityQueue(nodeLess)
 
 	// Create a node for each value and put it into a priority queue
 	for v, freq := range freqs {
@@ -280,17 +277,15 @@ func (c Coder) createTree(freqs map[interface{}]int) (*node, error) {
 
 	// While we still have at least two items, take them and merge
 	for pq.Size() > 1 {
-		pqItem, err = pq.DelMax()
+		n1, err = pq.DelMax()
 		if err != nil {
 			return parent, err
 		}
-		n1 = pqItem.(*node)
 
-		pqItem, err = pq.DelMax()
+		n2, err = pq.DelMax()
 		if err != nil {
 			return parent, err
 		}
-		n2 = pqItem.(*node)
 
 		// Create a null node with each of these children
 		// as different paths. Null nodes allow us to be
@@ -310,12 +305,10 @@ func (c Coder) createTree(freqs map[interface{}]int) (*node, error) {
 
 	// Add the final, highest priority node as root
 	if !pq.IsEmpty() {
-		pqItem, err := pq.DelMax()
+		parent, err = pq.DelMax()
 		if err != nil {
 			return parent, err
 		}
-
-		parent = pqItem.(*node)
 	}
 
 	return parent, nil
@@ -0,0 +1,224 @@
+package algo
+
+import (
+	"cmp"
+	"errors"
+)
+
+var PQDuplicate = errors.New("item already in priority queue")
+var PQKeyNotDecreased = errors.New("new key does not decrease the current key")
+var PQKeyNotIncreased = errors.New("new key does not increase the current key")
+
+// An indexed min priority queue. Each item is identified by a comparable
+// ID and carries an ordered key. Unlike PriorityQueue, the key of an item
+// that is already on the queue can be changed directly, which is what
+// algorithms like Dijkstra and Prim need.
+type IndexMinPQ[I comparable, K cmp.Ordered] struct {
+	// Our internal heap of items. To make math easier, we start
+	// storing data in heap[1]. heap[0] is unused.
+	heap []indexedItem[I, K]
+
+	// Given an ID, its index in our heap
+	pos map[I]int
+}
+
+// Private structure that pairs an ID with its current key
+type indexedItem[I comparable, K cmp.Ordered] struct {
+	id  I
+	key K
+}
+
+// Create a new, empty indexed min priority queue
+func NewIndexMinPQ[I comparable, K cmp.Ordered]() *IndexMinPQ[I, K] {
+	return &IndexMinPQ[I, K]{
+		heap: make([]indexedItem[I, K], 1),
+		pos:  make(map[I]int),
+	}
+}
+
+// Insert id with the given key. Returns PQDuplicate if id is already on
+// the queue.
+func (pq *IndexMinPQ[I, K]) Insert(id I, key K) error {
+	if pq.Contains(id) {
+		return PQDuplicate
+	}
+
+	// Store the item at the end of our heap and swim it up to the correct
+	// location
+	pq.heap = append(pq.heap, indexedItem[I, K]{id: id, key: key})
+	pq.pos[id] = pq.Size()
+	pq.swim(pq.Size())
+
+	return nil
+}
+
+// Do we contain this id?
+func (pq *IndexMinPQ[I, K]) Contains(id I) bool {
+	_, ok := pq.pos[id]
+
+	return ok
+}
+
+// KeyOf returns the current key of id, or NotFound if it is not on the
+// queue
+func (pq *IndexMinPQ[I, K]) KeyOf(id I) (K, error) {
+	i, ok := pq.pos[id]
+	if !ok {
+		var zero K
+		return zero, NotFound
+	}
+
+	return pq.heap[i].key, nil
+}
+
+// Min returns the id and key with the lowest key without deleting it
+func (pq *IndexMinPQ[I, K]) Min() (I, K, error) {
+	if pq.IsEmpty() {
+		var (
+			id  I
+			key K
+		)
+		return id, key, PQEmpty
+	}
+
+	return pq.heap[1].id, pq.heap[1].key, nil
+}
+
+// DelMin deletes the item with the lowest key and returns it
+func (pq *IndexMinPQ[I, K]) DelMin() (I, K, error) {
+	id, key, err := pq.Min()
+	if err != nil {
+		return id, key, err
+	}
+
+	pq.remove(1)
+
+	return id, key, nil
+}
+
+// Delete id from the queue. Returns NotFound if it is not on the queue.
+func (pq *IndexMinPQ[I, K]) Delete(id I) error {
+	i, ok := pq.pos[id]
+	if !ok {
+		return NotFound
+	}
+
+	pq.remove(i)
+
+	return nil
+}
+
+// DecreaseKey lowers the key of id. Returns PQKeyNotDecreased if key is not
+// strictly lower than the current key.
+func (pq *IndexMinPQ[I, K]) DecreaseKey(id I, key K) error {
+	i, ok := pq.pos[id]
+	if !ok {
+		return NotFound
+	}
+
+	if key >= pq.heap[i].key {
+		return PQKeyNotDecreased
+	}
+
+	// A lower key can only move up the heap
+	pq.heap[i].key = key
+	pq.swim(i)
+
+	return nil
+}
+
+// IncreaseKey raises the key of id. Returns PQKeyNotIncreased if key is not
+// strictly higher than the current key.
+func (pq *IndexMinPQ[I, K]) IncreaseKey(id I, key K) error {
+	i, ok := pq.pos[id]
+	if !ok {
+		return NotFound
+	}
+
+	if key <= pq.heap[i].key {
+		return PQKeyNotIncreased
+	}
+
+	// A higher key can only move down the heap
+	pq.heap[i].key = key
+	pq.sink(i)
+
+	return nil
+}
+
+// ChangeKey sets the key of id to any new value
+func (pq *IndexMinPQ[I, K]) ChangeKey(id I, key K) error {
+	i, ok := pq.pos[id]
+	if !ok {
+		return NotFound
+	}
+
+	// We don't know which direction it moved, so try both
+	pq.heap[i].key = key
+	pq.swim(i)
+	pq.sink(i)
+
+	return nil
+}
+
+// Is our priority queue empty?
+func (pq *IndexMinPQ[I, K]) IsEmpty() bool {
+	return pq.Size() < 1
+}
+
+// What is the current number of items in the priority queue?
+func (pq *IndexMinPQ[I, K]) Size() int {
+	return len(pq.heap) - 1
+}
+
+// Remove the item at index i and restore heap order
+func (pq *IndexMinPQ[I, K]) remove(i int) {
+	n := pq.Size()
+
+	// Swap this item with the last one and drop it off the end
+	pq.swap(i, n)
+	delete(pq.pos, pq.heap[n].id)
+	pq.heap = pq.heap[:n]
+
+	// Restore heap property if i wasn't the last index
+	if i < n {
+		pq.swim(i)
+		pq.sink(i)
+	}
+}
+
+// Starting from k, "swim" lower keys up toward index 1
+func (pq *IndexMinPQ[I, K]) swim(k int) {
+	for k > 1 && pq.heap[k].key < pq.heap[k/2].key {
+		pq.swap(k/2, k)
+		k = k / 2
+	}
+}
+
+// Starting with k, "sink" higher keys down the tree
+func (pq *IndexMinPQ[I, K]) sink(k int) {
+	n := pq.Size()
+
+	for 2*k <= n {
+		// Find the smaller of our two children
+		j := 2 * k
+		if j < n && pq.heap[j+1].key < pq.heap[j].key {
+			j++
+		}
+
+		// If the parent is already no bigger than the child, we're done
+		if pq.heap[k].key <= pq.heap[j].key {
+			break
+		}
+
+		pq.swap(k, j)
+		k = j
+	}
+}
+
+// Swap items at index i and j
+func (pq *IndexMinPQ[I, K]) swap(i, j int) {
+	pq.heap[i], pq.heap[j] = pq.heap[j], pq.heap[i]
+	pq.pos[pq.heap[i].id] = i
+	pq.pos[pq.heap[j].id] = j
+}
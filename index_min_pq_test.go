@@ -0,0 +1,135 @@
+package algo_test
+
+import (
+	"github.com/brnstz/algo"
+
+	"math/rand"
+	"testing"
+)
+
+// Test indexed priority queue by inserting random keys, changing half of
+// them and deleting everything in order
+func TestIndexMinPQ(t *testing.T) {
+	numVals := 10000
+
+	pq := algo.NewIndexMinPQ[int, int]()
+
+	for i := 0; i < numVals; i++ {
+		err := pq.Insert(i, rand.Intn(numVals))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if pq.Insert(0, 0) != algo.PQDuplicate {
+		t.Fatal("Expected duplicate error")
+	}
+
+	// Move every even ID somewhere else
+	for i := 0; i < numVals; i += 2 {
+		err := pq.ChangeKey(i, rand.Intn(numVals))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	size := pq.Size()
+	if size != numVals {
+		t.Fatalf("Expected exactly %v vals, but found %v", numVals, size)
+	}
+
+	lastKey := -1
+	for !pq.IsEmpty() {
+		id, key, err := pq.DelMin()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key < lastKey {
+			t.Fatalf("Expected ordering from lowest to highest key, but found sequence %v, %v", lastKey, key)
+		}
+
+		if pq.Contains(id) {
+			t.Fatalf("Expected %v to be deleted", id)
+		}
+
+		lastKey = key
+	}
+}
+
+// Test key updates on a small queue
+func TestIndexMinPQKeys(t *testing.T) {
+	pq := algo.NewIndexMinPQ[string, float64]()
+	pq.Insert("Thriller", 9000)
+	pq.Insert("Bad Romance", 600)
+	pq.Insert("1999", 200)
+	pq.Insert("Like a Rolling Stone", 10)
+
+	id, _, err := pq.Min()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "Like a Rolling Stone" {
+		t.Fatalf("Expected Like a Rolling Stone as min but got %v", id)
+	}
+
+	if pq.DecreaseKey("1999", 300) != algo.PQKeyNotDecreased {
+		t.Fatal("Expected key not decreased error")
+	}
+
+	if pq.IncreaseKey("1999", 100) != algo.PQKeyNotIncreased {
+		t.Fatal("Expected key not increased error")
+	}
+
+	err = pq.DecreaseKey("Thriller", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pq.IncreaseKey("Like a Rolling Stone", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := pq.KeyOf("Like a Rolling Stone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != 1000 {
+		t.Fatalf("Expected key of 1000 but got %v", key)
+	}
+
+	id, key, err = pq.DelMin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "Thriller" || key != 5 {
+		t.Fatalf("Expected Thriller @ 5 as min but got %v @ %v", id, key)
+	}
+
+	err = pq.Delete("1999")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pq.Delete("1999") != algo.NotFound {
+		t.Fatal("Expected not found error")
+	}
+
+	if _, err = pq.KeyOf("Thriller"); err != algo.NotFound {
+		t.Fatal("Expected not found error")
+	}
+
+	id, _, err = pq.DelMin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "Bad Romance" {
+		t.Fatalf("Expected Bad Romance as min but got %v", id)
+	}
+
+	pq.DelMin()
+	if _, _, err = pq.DelMin(); err != algo.PQEmpty {
+		t.Fatal("Expected empty priority queue")
+	}
+}
@@ -0,0 +1,147 @@
+package algo
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+)
+
+var BadInterval = errors.New("interval starts after it ends")
+
+// Interval is the closed range of values from Lo to Hi. Intervals are
+// ordered by Lo, then by Hi.
+type Interval[T cmp.Ordered] struct {
+	Lo, Hi T
+}
+
+func (i Interval[T]) Less(other_ NodeValue) bool {
+	other := other_.(Interval[T])
+	return i.Lo < other.Lo || (i.Lo == other.Lo && i.Hi < other.Hi)
+}
+
+func (i Interval[T]) Equals(other_ NodeValue) bool {
+	other := other_.(Interval[T])
+	return i.Lo == other.Lo && i.Hi == other.Hi
+}
+
+// Does this interval share any values with the range from lo to hi?
+func (i Interval[T]) Overlaps(lo, hi T) bool {
+	return i.Lo <= hi && lo <= i.Hi
+}
+
+// IntervalTree stores intervals on a RedBlackTree ordered by their low
+// end. Each node also tracks the highest Hi of any interval under it, so
+// queries can skip whole subtrees that end before the range we're looking
+// for. Putting the same interval twice counts a dupe, like RedBlackTree.
+// The zero value is an empty tree ready to use.
+type IntervalTree[T cmp.Ordered] struct {
+	tree RedBlackTree
+}
+
+// Create a new, empty interval tree
+func NewIntervalTree[T cmp.Ordered]() *IntervalTree[T] {
+	it := &IntervalTree[T]{}
+	it.init()
+
+	return it
+}
+
+// Private helper to have the tree keep our max endpoints up to date. It
+// has to be set before the first Put, so call it before any change.
+func (it *IntervalTree[T]) init() {
+	if it.tree.augment == nil {
+		it.tree.augment = it.updateMax
+	}
+}
+
+// Insert an interval into the tree. Returns BadInterval if i.Lo > i.Hi.
+func (it *IntervalTree[T]) Insert(i Interval[T]) error {
+	if i.Lo > i.Hi {
+		return BadInterval
+	}
+
+	it.init()
+	it.tree.Put(i)
+
+	return nil
+}
+
+// Delete one copy of an interval from the tree. Returns false if it is not
+// in the tree.
+func (it *IntervalTree[T]) Delete(i Interval[T]) bool {
+	it.init()
+
+	return it.tree.Delete(i)
+}
+
+// How many distinct intervals are in the tree?
+func (it *IntervalTree[T]) Size() int {
+	return it.tree.Size()
+}
+
+// All returns an iterator over every distinct interval in the tree, in
+// order
+func (it *IntervalTree[T]) All() iter.Seq[Interval[T]] {
+	return func(yield func(Interval[T]) bool) {
+		for v := range it.tree.All() {
+			if !yield(v.(Interval[T])) {
+				return
+			}
+		}
+	}
+}
+
+// Overlapping returns an iterator over every distinct interval in the tree
+// that shares any values with the range from lo to hi, in order
+func (it *IntervalTree[T]) Overlapping(lo, hi T) iter.Seq[Interval[T]] {
+	return func(yield func(Interval[T]) bool) {
+		it.overlapping(it.tree.Root, lo, hi, yield)
+	}
+}
+
+// Stabbing returns an iterator over every distinct interval in the tree
+// that contains point, in order
+func (it *IntervalTree[T]) Stabbing(point T) iter.Seq[Interval[T]] {
+	return it.Overlapping(point, point)
+}
+
+// Recursively yield intervals under n that overlap lo to hi. Returns false
+// if yield asked us to stop.
+func (it *IntervalTree[T]) overlapping(n *Node, lo, hi T, yield func(Interval[T]) bool) bool {
+	// If nothing under n ends at or after lo, nothing here can overlap
+	if n == nil || n.meta.(T) < lo {
+		return true
+	}
+
+	if !it.overlapping(n.Left, lo, hi, yield) {
+		return false
+	}
+
+	i := n.Value.(Interval[T])
+
+	// Everything on the right starts at or after i.Lo, so if i starts
+	// after hi, we're done
+	if i.Lo > hi {
+		return true
+	}
+
+	if i.Overlaps(lo, hi) && !yield(i) {
+		return false
+	}
+
+	return it.overlapping(n.Right, lo, hi, yield)
+}
+
+// Recalculate the highest Hi under n from its interval and its children
+func (it *IntervalTree[T]) updateMax(n *Node) {
+	hi := n.Value.(Interval[T]).Hi
+
+	if n.Left != nil {
+		hi = max(hi, n.Left.meta.(T))
+	}
+	if n.Right != nil {
+		hi = max(hi, n.Right.meta.(T))
+	}
+
+	n.meta = hi
+}
@@ -0,0 +1,155 @@
+package algo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Check that every node knows the highest Hi under it
+func checkIntervalMax(t *testing.T, n *Node) int {
+	if n == nil {
+		return -1
+	}
+
+	hi := max(n.Value.(Interval[int]).Hi, checkIntervalMax(t, n.Left), checkIntervalMax(t, n.Right))
+	if n.meta.(int) != hi {
+		t.Fatalf("Expected max %v at %v but got %v", hi, n.Value, n.meta)
+	}
+
+	return hi
+}
+
+// Compare overlapping queries against checking every interval
+func checkOverlapping(t *testing.T, tree *IntervalTree[int], expected map[Interval[int]]int, lo, hi int) {
+	count := 0
+	var last *Interval[int]
+
+	for i := range tree.Overlapping(lo, hi) {
+		if !i.Overlaps(lo, hi) {
+			t.Fatalf("%v doesn't overlap %v-%v", i, lo, hi)
+		}
+		if last != nil && !last.Less(i) {
+			t.Fatalf("%v came after %v", i, *last)
+		}
+		last = &i
+		count++
+	}
+
+	expectedCount := 0
+	for i := range expected {
+		if i.Overlaps(lo, hi) {
+			expectedCount++
+		}
+	}
+
+	if count != expectedCount {
+		t.Fatalf("Expected %v intervals overlapping %v-%v but got %v", expectedCount, lo, hi, count)
+	}
+}
+
+func TestIntervalTree(t *testing.T) {
+	numVals := 2000
+	r := rand.New(rand.NewSource(1))
+
+	tree := NewIntervalTree[int]()
+	expected := map[Interval[int]]int{}
+
+	if tree.Insert(Interval[int]{5, 4}) != BadInterval {
+		t.Fatal("Expected bad interval")
+	}
+
+	for i := 0; i < numVals; i++ {
+		lo := r.Intn(numVals * 10)
+		in := Interval[int]{lo, lo + r.Intn(100)}
+
+		err := tree.Insert(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected[in]++
+	}
+
+	if tree.Size() != len(expected) {
+		t.Fatalf("Expected size %v but got %v", len(expected), tree.Size())
+	}
+	checkIntervalMax(t, tree.tree.Root)
+
+	for i := 0; i < 200; i++ {
+		lo := r.Intn(numVals * 10)
+		checkOverlapping(t, tree, expected, lo, lo+r.Intn(200))
+		checkOverlapping(t, tree, expected, lo, lo)
+	}
+
+	// Stabbing is just an overlap with a single point
+	count := 0
+	for i := range tree.Stabbing(100) {
+		if i.Lo > 100 || i.Hi < 100 {
+			t.Fatalf("%v doesn't contain 100", i)
+		}
+		count++
+	}
+	for i := range expected {
+		if i.Lo <= 100 && i.Hi >= 100 {
+			count--
+		}
+	}
+	if count != 0 {
+		t.Fatalf("Stabbing 100 is off by %v", count)
+	}
+
+	// Delete about half, one copy at a time
+	for in := range expected {
+		if r.Intn(2) == 0 {
+			continue
+		}
+
+		if !tree.Delete(in) {
+			t.Fatalf("Can't delete %v", in)
+		}
+		if expected[in] > 1 {
+			expected[in]--
+		} else {
+			delete(expected, in)
+		}
+	}
+
+	if tree.Delete(Interval[int]{-1, -1}) {
+		t.Fatal("Deleted interval not in tree")
+	}
+
+	if tree.Size() != len(expected) {
+		t.Fatalf("Expected size %v but got %v", len(expected), tree.Size())
+	}
+	checkIntervalMax(t, tree.tree.Root)
+	checkTree(t, &tree.tree)
+
+	for i := 0; i < 200; i++ {
+		lo := r.Intn(numVals * 10)
+		checkOverlapping(t, tree, expected, lo, lo+r.Intn(200))
+	}
+}
+
+// The zero value should work without NewIntervalTree
+func TestIntervalTreeZeroValue(t *testing.T) {
+	var it IntervalTree[int]
+
+	for _, i := range []Interval[int]{{5, 10}, {1, 3}, {8, 20}, {2, 2}} {
+		if err := it.Insert(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkIntervalMax(t, it.tree.Root)
+
+	var found []Interval[int]
+	for i := range it.Stabbing(9) {
+		found = append(found, i)
+	}
+	if len(found) != 2 || found[0] != (Interval[int]{5, 10}) || found[1] != (Interval[int]{8, 20}) {
+		t.Fatalf("Expected [{5 10} {8 20}] but got %v", found)
+	}
+
+	if !it.Delete(Interval[int]{8, 20}) {
+		t.Fatal("Expected to delete {8 20}")
+	}
+	checkIntervalMax(t, it.tree.Root)
+}
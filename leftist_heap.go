@@ -0,0 +1,120 @@
+package algo
+
+// LeftistHeap is a priority queue stored as a binary tree where the right
+// spine is always the shortest path to a leaf. That keeps the right spine
+// O(log n) long, so two heaps can be melded by walking down their right
+// spines. Items are ordered by the same PQLessFunc used by PriorityQueue.
+type LeftistHeap[T any] struct {
+	root *heapNode[T]
+	n    int
+	less PQLessFunc[T]
+}
+
+// A node on a LeftistHeap or SkewHeap
+type heapNode[T any] struct {
+	item  T
+	left  *heapNode[T]
+	right *heapNode[T]
+
+	// Length of the shortest path to a nil child. Only used by
+	// LeftistHeap.
+	rank int
+}
+
+// Create a new, empty leftist heap ordered by less
+func NewLeftistHeap[T any](less PQLessFunc[T]) *LeftistHeap[T] {
+	return &LeftistHeap[T]{less: less}
+}
+
+// Insert a new item into the heap
+func (h *LeftistHeap[T]) Insert(item T) {
+	h.root = h.meld(h.root, &heapNode[T]{item: item, rank: 1})
+	h.n++
+}
+
+// GetMax returns the highest priority item without deleting it
+func (h *LeftistHeap[T]) GetMax() (T, error) {
+	if h.IsEmpty() {
+		var zero T
+		return zero, PQEmpty
+	}
+
+	return h.root.item, nil
+}
+
+// Delete the highest priority item in our heap
+func (h *LeftistHeap[T]) DelMax() (T, error) {
+	if h.IsEmpty() {
+		var zero T
+		return zero, PQEmpty
+	}
+
+	max := h.root.item
+
+	// The new root comes from melding the two subtrees of the old one
+	h.root = h.meld(h.root.left, h.root.right)
+	h.n--
+
+	return max, nil
+}
+
+// Meld moves every item from other into this heap in O(log n) time,
+// leaving other empty. Both heaps must use the same ordering.
+func (h *LeftistHeap[T]) Meld(other *LeftistHeap[T]) {
+	if other == h {
+		return
+	}
+
+	h.root = h.meld(h.root, other.root)
+	h.n += other.n
+
+	other.root = nil
+	other.n = 0
+}
+
+// Is our heap empty?
+func (h *LeftistHeap[T]) IsEmpty() bool {
+	return h.n < 1
+}
+
+// What is the current number of items on the heap?
+func (h *LeftistHeap[T]) Size() int {
+	return h.n
+}
+
+// Recursively meld the trees at a and b, returning the new root
+func (h *LeftistHeap[T]) meld(a, b *heapNode[T]) *heapNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	// Keep the higher priority node as our root
+	if h.less(a.item, b.item) {
+		a, b = b, a
+	}
+
+	// Meld down the right spine
+	a.right = h.meld(a.right, b)
+
+	// Restore the leftist property by keeping the shorter path on the
+	// right
+	if a.left.rankZeroNil() < a.right.rankZeroNil() {
+		a.left, a.right = a.right, a.left
+	}
+
+	a.rank = a.right.rankZeroNil() + 1
+
+	return a
+}
+
+// Private helper function to get the rank of a possibly nil node
+func (n *heapNode[T]) rankZeroNil() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.rank
+}
@@ -0,0 +1,134 @@
+package algo_test
+
+import (
+	"github.com/brnstz/algo"
+
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// The methods shared by LeftistHeap and SkewHeap, so we can use the same
+// test code
+type mergeableHeap[H any] interface {
+	Insert(PQInt)
+	DelMax() (PQInt, error)
+	Meld(H)
+	Size() int
+	IsEmpty() bool
+}
+
+// Fill numHeaps heaps with random values, meld them all into the first one
+// and check that everything comes out in order
+func testMeld[H mergeableHeap[H]](t *testing.T, newHeap func() H) {
+	numHeaps := 100
+	numVals := 1000
+
+	heaps := make([]H, numHeaps)
+	for i := range heaps {
+		heaps[i] = newHeap()
+
+		for j := 0; j < numVals; j++ {
+			heaps[i].Insert(PQInt(rand.Int31()))
+		}
+	}
+
+	for i := 1; i < numHeaps; i++ {
+		heaps[0].Meld(heaps[i])
+
+		if !heaps[i].IsEmpty() {
+			t.Fatal("Expected melded heap to be empty")
+		}
+	}
+
+	size := heaps[0].Size()
+	if size != numHeaps*numVals {
+		t.Fatalf("Expected exactly %v vals, but found %v", numHeaps*numVals, size)
+	}
+
+	var lastVal PQInt = math.MaxInt32
+	for !heaps[0].IsEmpty() {
+		val, err := heaps[0].DelMax()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if val > lastVal {
+			t.Fatalf("Expected ordering from highest int to lowest int, but found sequenece %v, %v", lastVal, val)
+		}
+		lastVal = val
+	}
+
+	if _, err := heaps[0].DelMax(); err != algo.PQEmpty {
+		t.Fatal("Expected empty heap")
+	}
+}
+
+func TestLeftistHeapMeld(t *testing.T) {
+	testMeld(t, func() *algo.LeftistHeap[PQInt] {
+		return algo.NewLeftistHeap(pqIntLess)
+	})
+}
+
+func TestSkewHeapMeld(t *testing.T) {
+	testMeld(t, func() *algo.SkewHeap[PQInt] {
+		return algo.NewSkewHeap(pqIntLess)
+	})
+}
+
+// Sorted inserts build long paths in a skew heap, which shouldn't be a
+// problem for meld
+func TestSkewHeapSorted(t *testing.T) {
+	numVals := 1000000
+
+	up := algo.NewSkewHeap(pqIntLess)
+	down := algo.NewSkewHeap(pqIntLess)
+	for i := 0; i < numVals; i++ {
+		up.Insert(PQInt(i))
+		down.Insert(PQInt(numVals - i - 1))
+	}
+
+	up.Meld(down)
+
+	for i := numVals - 1; i >= 0; i-- {
+		for j := 0; j < 2; j++ {
+			val, err := up.DelMax()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if val != PQInt(i) {
+				t.Fatalf("Expected %v but got %v", i, val)
+			}
+		}
+	}
+
+	if !up.IsEmpty() {
+		t.Fatal("Expected empty heap")
+	}
+}
+
+// The same less function works on a PriorityQueue and a LeftistHeap
+func TestLeftistHeapJukebox(t *testing.T) {
+	item1 := &JukeboxSong{"Thriller", 9000}
+	item2 := &JukeboxSong{"Bad Romance", 600}
+	item3 := &JukeboxSong{"1999", 200}
+	item4 := &JukeboxSong{"Like a Rolling Stone", 10}
+
+	h1 := algo.NewLeftistHeap(jukeboxSongLess)
+	h1.Insert(item1)
+	h1.Insert(item2)
+
+	h2 := algo.NewLeftistHeap(jukeboxSongLess)
+	h2.Insert(item3)
+	h2.Insert(item4)
+
+	h1.Meld(h2)
+
+	top, err := h1.GetMax()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if top != item4 {
+		t.Fatalf("Expected %+v as top item, but got %+v", item4, top)
+	}
+}
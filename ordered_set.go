@@ -0,0 +1,53 @@
+package algo
+
+import (
+	"iter"
+)
+
+// OrderedSet is a balanced search tree of NodeValues. Putting a value that
+// is already in the set counts a dupe, and Delete removes one copy at a
+// time. Size and the iterators only see each distinct value once.
+type OrderedSet interface {
+	// Put a new value into the set
+	Put(NodeValue)
+
+	// Can we find value v in the set?
+	Find(NodeValue) bool
+
+	// Delete one copy of a value, returns false if it's not in the set
+	Delete(NodeValue) bool
+
+	// How many distinct values are in the set?
+	Size() int
+
+	// Smallest value in the set, or EmptyTree
+	Min() (NodeValue, error)
+
+	// Largest value in the set, or EmptyTree
+	Max() (NodeValue, error)
+
+	// Iterate over every distinct value from smallest to largest
+	All() iter.Seq[NodeValue]
+
+	// Iterate over every distinct value between lo and hi, inclusive
+	Range(lo, hi NodeValue) iter.Seq[NodeValue]
+}
+
+// Make sure each of our trees implements OrderedSet
+var (
+	_ OrderedSet = &RedBlackTree{}
+	_ OrderedSet = &AVLTree{}
+	_ OrderedSet = &Treap{}
+	_ OrderedSet = &BTree{}
+	_ OrderedSet = &ConcurrentRedBlackTree{}
+)
+
+// Is v at least lo? A nil lo is unbounded.
+func atLeast(v, lo NodeValue) bool {
+	return lo == nil || !v.Less(lo)
+}
+
+// Is v at most hi? A nil hi is unbounded.
+func atMost(v, hi NodeValue) bool {
+	return hi == nil || !hi.Less(v)
+}
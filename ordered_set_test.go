@@ -0,0 +1,229 @@
+package algo_test
+
+import (
+	"github.com/brnstz/algo"
+
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// Implement NodeValue interface for ints
+type intValue int
+
+func (i intValue) Less(other algo.NodeValue) bool {
+	return i < other.(intValue)
+}
+
+func (i intValue) Equals(other algo.NodeValue) bool {
+	return i == other.(intValue)
+}
+
+// Every OrderedSet implementation we want to test
+var orderedSets = []struct {
+	name   string
+	newSet func() algo.OrderedSet
+}{
+	{"redblack", func() algo.OrderedSet { return &algo.RedBlackTree{} }},
+	{"avl", func() algo.OrderedSet { return algo.NewAVLTree() }},
+	{"treap", func() algo.OrderedSet { return algo.NewTreap() }},
+	{"btree-4", func() algo.OrderedSet { return algo.NewBTree(4) }},
+	{"btree-32", func() algo.OrderedSet { return algo.NewBTree(32) }},
+	{"concurrent", func() algo.OrderedSet { return algo.NewConcurrentRedBlackTree() }},
+}
+
+// Check that set holds exactly the values in expected, in order
+func checkOrderedSet(t *testing.T, name string, set algo.OrderedSet, expected map[intValue]int) {
+	var sorted []int
+	for v := range expected {
+		sorted = append(sorted, int(v))
+	}
+	sort.Ints(sorted)
+
+	if set.Size() != len(sorted) {
+		t.Fatalf("%v: expected size %v but got %v", name, len(sorted), set.Size())
+	}
+
+	i := 0
+	for v := range set.All() {
+		if i >= len(sorted) || v != intValue(sorted[i]) {
+			t.Fatalf("%v: unexpected value %v at %v", name, v, i)
+		}
+		i++
+	}
+	if i != len(sorted) {
+		t.Fatalf("%v: expected %v values but got %v", name, len(sorted), i)
+	}
+
+	if len(sorted) == 0 {
+		if _, err := set.Min(); err != algo.EmptyTree {
+			t.Fatalf("%v: expected empty tree", name)
+		}
+		if _, err := set.Max(); err != algo.EmptyTree {
+			t.Fatalf("%v: expected empty tree", name)
+		}
+		return
+	}
+
+	min, err := set.Min()
+	if err != nil || min != intValue(sorted[0]) {
+		t.Fatalf("%v: expected min %v but got %v, %v", name, sorted[0], min, err)
+	}
+
+	max, err := set.Max()
+	if err != nil || max != intValue(sorted[len(sorted)-1]) {
+		t.Fatalf("%v: expected max %v but got %v, %v", name, sorted[len(sorted)-1], max, err)
+	}
+}
+
+// Run the same random sequence of puts and deletes against every set and
+// compare with a map
+func TestOrderedSetConformance(t *testing.T) {
+	numVals := 3000
+
+	for _, tc := range orderedSets {
+		r := rand.New(rand.NewSource(1))
+		set := tc.newSet()
+		expected := map[intValue]int{}
+
+		checkOrderedSet(t, tc.name, set, expected)
+
+		// Mostly puts with some dupes
+		for i := 0; i < numVals; i++ {
+			v := intValue(r.Intn(numVals))
+			set.Put(v)
+			expected[v]++
+		}
+		checkOrderedSet(t, tc.name, set, expected)
+
+		for v := range expected {
+			if !set.Find(v) {
+				t.Fatalf("%v: can't find %v", tc.name, v)
+			}
+		}
+
+		if set.Find(intValue(-1)) || set.Delete(intValue(-1)) {
+			t.Fatalf("%v: found value not in set", tc.name)
+		}
+
+		// Range bounds don't need to be in the set
+		count := 0
+		last := intValue(-1)
+		for v := range set.Range(intValue(100), intValue(200)) {
+			if v.(intValue) < 100 || v.(intValue) > 200 || v.(intValue) <= last {
+				t.Fatalf("%v: unexpected value %v in range", tc.name, v)
+			}
+			last = v.(intValue)
+			count++
+		}
+		expectedCount := 0
+		for v := range expected {
+			if v >= 100 && v <= 200 {
+				expectedCount++
+			}
+		}
+		if count != expectedCount {
+			t.Fatalf("%v: expected %v values in range but got %v", tc.name, expectedCount, count)
+		}
+
+		// Delete a random mix, one copy at a time
+		for i := 0; i < numVals*2; i++ {
+			v := intValue(r.Intn(numVals))
+
+			deleted := set.Delete(v)
+			if deleted != (expected[v] > 0) {
+				t.Fatalf("%v: delete %v returned %v but count is %v", tc.name, v, deleted, expected[v])
+			}
+
+			if expected[v] > 1 {
+				expected[v]--
+			} else {
+				delete(expected, v)
+			}
+
+			if set.Find(v) != (expected[v] > 0) {
+				t.Fatalf("%v: find %v is wrong after delete", tc.name, v)
+			}
+
+			if i%500 == 0 {
+				checkOrderedSet(t, tc.name, set, expected)
+			}
+		}
+		checkOrderedSet(t, tc.name, set, expected)
+
+		// Delete everything that's left
+		for v, n := range expected {
+			for ; n > 0; n-- {
+				if !set.Delete(v) {
+					t.Fatalf("%v: can't delete %v", tc.name, v)
+				}
+			}
+		}
+		checkOrderedSet(t, tc.name, set, map[intValue]int{})
+	}
+}
+
+// The binary trees should stay balanced even when values are put in order
+func TestOrderedSetHeight(t *testing.T) {
+	numVals := 10000
+
+	trees := map[string]interface {
+		algo.OrderedSet
+		Height() int
+	}{
+		"redblack": &algo.RedBlackTree{},
+		"avl":      algo.NewAVLTree(),
+		"treap":    algo.NewTreap(),
+	}
+
+	for name, tree := range trees {
+		for i := 0; i < numVals; i++ {
+			tree.Put(intValue(i))
+		}
+
+		// Neither tree should ever be more than about twice as high as a
+		// perfectly balanced tree. Give the treap some extra room since it
+		// is only balanced with high probability.
+		maxHeight := 2 * math.Log2(float64(numVals+1))
+		if name == "treap" {
+			maxHeight *= 2
+		}
+
+		if float64(tree.Height()) > maxHeight {
+			t.Fatalf("%v is too high, actual: %v, expected < %v", name, tree.Height(), maxHeight)
+		}
+	}
+}
+
+// Compare sets on workloads with different read/write mixes
+func BenchmarkOrderedSet(b *testing.B) {
+	numVals := 100000
+
+	for _, readPct := range []int{10, 50, 90} {
+		for _, tc := range orderedSets {
+			b.Run(fmt.Sprintf("%v-read%v", tc.name, readPct), func(b *testing.B) {
+				r := rand.New(rand.NewSource(1))
+				set := tc.newSet()
+
+				for i := 0; i < numVals; i++ {
+					set.Put(intValue(r.Intn(numVals)))
+				}
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					v := intValue(r.Intn(numVals))
+
+					if r.Intn(100) < readPct {
+						set.Find(v)
+					} else if i%2 == 0 {
+						set.Put(v)
+					} else {
+						set.Delete(v)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package algo
+
+import (
+	"cmp"
+)
+
+// PairingHeap is a heap-ordered multiway tree. Insert, DecreaseKey and
+// Merge take O(1) time, with the work deferred to ExtractMin, which pairs up
+// the children of the old root in two passes.
+type PairingHeap[K cmp.Ordered, V any] struct {
+	root *HeapItem[K, V]
+	n    int
+
+	// Which heap our items are on
+	owner *heapOwner
+}
+
+// Each item links to its first child, its next sibling (right) and its
+// previous sibling (left). The first child of a node uses left to point to
+// its parent instead.
+
+// Create a new, empty pairing heap
+func NewPairingHeap[K cmp.Ordered, V any]() *PairingHeap[K, V] {
+	return &PairingHeap[K, V]{}
+}
+
+// Insert value with key
+func (h *PairingHeap[K, V]) Insert(key K, value V) *HeapItem[K, V] {
+	item := &HeapItem[K, V]{
+		key:    key,
+		value:  value,
+		queued: true,
+		owner:  ensureOwner(&h.owner),
+	}
+
+	h.root = h.meld(h.root, item)
+	h.n++
+
+	return item
+}
+
+// Min returns the item with the lowest key without deleting it
+func (h *PairingHeap[K, V]) Min() (*HeapItem[K, V], error) {
+	if h.IsEmpty() {
+		return nil, PQEmpty
+	}
+
+	return h.root, nil
+}
+
+// ExtractMin deletes the item with the lowest key and returns it
+func (h *PairingHeap[K, V]) ExtractMin() (*HeapItem[K, V], error) {
+	if h.IsEmpty() {
+		return nil, PQEmpty
+	}
+
+	min := h.root
+	h.root = h.mergePairs(min.child)
+	h.n--
+
+	min.child = nil
+	min.queued = false
+
+	return min, nil
+}
+
+// DecreaseKey lowers the key of item
+func (h *PairingHeap[K, V]) DecreaseKey(item *HeapItem[K, V], key K) error {
+	err := item.checkDecrease(h.owner, key)
+	if err != nil {
+		return err
+	}
+
+	item.key = key
+
+	// If it's not the root, cut its subtree out and meld it back in
+	// with the root
+	if item != h.root {
+		h.detach(item)
+		h.root = h.meld(h.root, item)
+	}
+
+	return nil
+}
+
+// Merge moves every item from other onto this heap in O(1) time
+func (h *PairingHeap[K, V]) Merge(other MinHeap[K, V]) error {
+	o, ok := other.(*PairingHeap[K, V])
+	if !ok {
+		return HeapMismatch
+	}
+
+	if o == h {
+		return nil
+	}
+
+	mergeOwner(&h.owner, &o.owner)
+
+	h.root = h.meld(h.root, o.root)
+	h.n += o.n
+
+	o.root = nil
+	o.n = 0
+
+	return nil
+}
+
+// What is the current number of items on the heap?
+func (h *PairingHeap[K, V]) Size() int {
+	return h.n
+}
+
+// Is our heap empty?
+func (h *PairingHeap[K, V]) IsEmpty() bool {
+	return h.n < 1
+}
+
+// Combine two trees by making the root with the larger key the first child
+// of the other. Returns the new root.
+func (h *PairingHeap[K, V]) meld(a, b *HeapItem[K, V]) *HeapItem[K, V] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	if b.key < a.key {
+		a, b = b, a
+	}
+
+	b.right = a.child
+	if a.child != nil {
+		a.child.left = b
+	}
+	b.left = a
+	a.child = b
+
+	return a
+}
+
+// Remove the subtree at item from its parent and siblings
+func (h *PairingHeap[K, V]) detach(item *HeapItem[K, V]) {
+	if item.left.child == item {
+		// We're the first child, so our parent now points to our sibling
+		item.left.child = item.right
+	} else {
+		item.left.right = item.right
+	}
+
+	if item.right != nil {
+		item.right.left = item.left
+	}
+
+	item.left = nil
+	item.right = nil
+}
+
+// Meld a list of siblings into one tree, first pairing them from left to
+// right and then melding the pairs from right to left. Returns the new root.
+func (h *PairingHeap[K, V]) mergePairs(first *HeapItem[K, V]) *HeapItem[K, V] {
+	var trees []*HeapItem[K, V]
+
+	// Unlink every sibling
+	for item := first; item != nil; {
+		next := item.right
+		item.left = nil
+		item.right = nil
+		trees = append(trees, item)
+		item = next
+	}
+
+	if len(trees) < 1 {
+		return nil
+	}
+
+	// First pass, left to right
+	paired := trees[:0]
+	for i := 0; i < len(trees); i += 2 {
+		if i+1 < len(trees) {
+			paired = append(paired, h.meld(trees[i], trees[i+1]))
+		} else {
+			paired = append(paired, trees[i])
+		}
+	}
+
+	// Second pass, right to left
+	root := paired[len(paired)-1]
+	for i := len(paired) - 2; i >= 0; i-- {
+		root = h.meld(paired[i], root)
+	}
+
+	return root
+}
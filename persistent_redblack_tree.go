@@ -0,0 +1,93 @@
+package algo
+
+import (
+	"iter"
+	"sync/atomic"
+)
+
+// Every change to a PersistentRedBlackTree gets a new version, so that no
+// two trees ever own the same nodes
+var persistentVersion atomic.Uint64
+
+// PersistentRedBlackTree is an immutable RedBlackTree. Put and Delete
+// return a new tree and leave the original one unchanged. Only the nodes on
+// the path to the change are copied, everything else is shared between
+// versions.
+//
+// Since a tree never changes, any number of goroutines can read it while
+// another goroutine creates new versions. Publishing a new version to
+// readers still needs to be synchronized, for example with atomic.Pointer.
+//
+// The zero value is an empty tree.
+type PersistentRedBlackTree struct {
+	root *Node
+}
+
+// Put returns a new tree with v added
+func (t PersistentRedBlackTree) Put(v NodeValue) PersistentRedBlackTree {
+	w := t.writer()
+	w.Put(v)
+
+	return PersistentRedBlackTree{root: w.Root}
+}
+
+// Delete returns a new tree with one copy of v removed. Returns the same
+// tree and false if v is not in the tree.
+func (t PersistentRedBlackTree) Delete(v NodeValue) (PersistentRedBlackTree, bool) {
+	w := t.writer()
+	if !w.Delete(v) {
+		return t, false
+	}
+
+	return PersistentRedBlackTree{root: w.Root}, true
+}
+
+// Can we find value v in the tree?
+func (t PersistentRedBlackTree) Find(v NodeValue) bool {
+	return t.reader().Find(v)
+}
+
+// How many distinct values are in the tree?
+func (t PersistentRedBlackTree) Size() int {
+	return t.reader().Size()
+}
+
+// What is the height of the tree?
+func (t PersistentRedBlackTree) Height() int {
+	return t.reader().Height()
+}
+
+// Smallest value in the tree
+func (t PersistentRedBlackTree) Min() (NodeValue, error) {
+	return t.reader().Min()
+}
+
+// Largest value in the tree
+func (t PersistentRedBlackTree) Max() (NodeValue, error) {
+	return t.reader().Max()
+}
+
+// All returns an iterator over every distinct value in the tree, from
+// smallest to largest
+func (t PersistentRedBlackTree) All() iter.Seq[NodeValue] {
+	return t.reader().All()
+}
+
+// Range returns an iterator over every distinct value in the tree between
+// lo and hi, inclusive, from smallest to largest
+func (t PersistentRedBlackTree) Range(lo, hi NodeValue) iter.Seq[NodeValue] {
+	return t.reader().Range(lo, hi)
+}
+
+// A RedBlackTree that copies any node it changes
+func (t PersistentRedBlackTree) writer() *RedBlackTree {
+	return &RedBlackTree{
+		Root:    t.root,
+		version: persistentVersion.Add(1),
+	}
+}
+
+// A RedBlackTree for read only methods
+func (t PersistentRedBlackTree) reader() *RedBlackTree {
+	return &RedBlackTree{Root: t.root}
+}
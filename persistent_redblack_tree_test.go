@@ -0,0 +1,135 @@
+package algo
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Keep every version of a tree while putting and deleting values, then
+// check that each old version is unchanged
+func TestPersistentRedBlack(t *testing.T) {
+	numVals := 500
+
+	var (
+		versions []PersistentRedBlackTree
+		contents []map[intNode]bool
+	)
+
+	tree := PersistentRedBlackTree{}
+	current := map[intNode]bool{}
+
+	for i := 0; i < numVals*2; i++ {
+		v := intNode(rand.Intn(numVals))
+
+		if current[v] {
+			var ok bool
+			tree, ok = tree.Delete(v)
+			if !ok {
+				t.Fatalf("Can't delete %v", v)
+			}
+			delete(current, v)
+		} else {
+			tree = tree.Put(v)
+			current[v] = true
+		}
+
+		checkTree(t, &RedBlackTree{Root: tree.root})
+
+		// Save a copy of this version and what should be in it
+		versions = append(versions, tree)
+		saved := map[intNode]bool{}
+		for v := range current {
+			saved[v] = true
+		}
+		contents = append(contents, saved)
+	}
+
+	for i, version := range versions {
+		if version.Size() != len(contents[i]) {
+			t.Fatalf("Version %v should have %v values but has %v", i, len(contents[i]), version.Size())
+		}
+
+		for v := range version.All() {
+			if !contents[i][v.(intNode)] {
+				t.Fatalf("Version %v has unexpected value %v", i, v)
+			}
+		}
+	}
+
+	if _, ok := tree.Delete(intNode(-1)); ok {
+		t.Fatal("Deleted value not in tree")
+	}
+
+	// Deleting a dupe from a new version leaves the old count alone
+	dupes := tree.Put(intNode(-1)).Put(intNode(-1))
+	single, _ := dupes.Delete(intNode(-1))
+
+	if dupes.reader().find(dupes.root, intNode(-1)).ValueCount != 2 {
+		t.Fatal("Expected old version to keep both dupes")
+	}
+
+	if single.reader().find(single.root, intNode(-1)).ValueCount != 1 {
+		t.Fatal("Expected new version to have one dupe")
+	}
+}
+
+// Readers iterate over the latest version while a writer keeps creating
+// new ones. Run with -race.
+func TestPersistentRedBlackConcurrent(t *testing.T) {
+	numVals := 2000
+
+	var latest atomic.Pointer[PersistentRedBlackTree]
+	latest.Store(&PersistentRedBlackTree{})
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				// Every snapshot should be sorted and have the size it claims
+				tree := latest.Load()
+				count := 0
+				last := intNode(-1)
+				for v := range tree.All() {
+					if v.(intNode) <= last {
+						t.Errorf("Snapshot out of order: %v, %v", last, v)
+						return
+					}
+					last = v.(intNode)
+					count++
+				}
+
+				if count != tree.Size() {
+					t.Errorf("Snapshot has %v values but claims %v", count, tree.Size())
+					return
+				}
+			}
+		}()
+	}
+
+	tree := *latest.Load()
+	for _, v := range rand.Perm(numVals) {
+		tree = tree.Put(intNode(v))
+		if v%3 == 0 {
+			tree, _ = tree.Delete(intNode(v))
+		}
+
+		next := tree
+		latest.Store(&next)
+	}
+
+	close(done)
+	wg.Wait()
+}
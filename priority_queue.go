@@ -4,49 +4,66 @@ import (
 	"errors"
 )
 
-// A priority queue that can hold any set of items that implements
-// the PQItem interface
-type PriorityQueue struct {
+// A priority queue that can hold any comparable type T, ordered by a
+// caller-supplied less function
+type PriorityQueue[T comparable] struct {
 	// Our internal store of data. To make math easier, we start
 	// storing data in data[1]. data[0] is unused.
-	data []PQItem
+	data []T
 
 	// The current number of actual items
 	n int
 
-	// The max number of items we can store
+	// The max number of items we can store. If we are not static, this is
+	// only our current physical size, which changes as we resize.
 	maxN int
 
-	// Given a PQItem, its index in our data array
-	revMap map[PQItem]int
-}
+	// Is this priority queue of a static size?
+	static bool
+
+	// Given an item, its index in our data array
+	revMap map[T]int
 
-// Implement this interface to use this type on a PriorityQueue
-type PQItem interface {
-	// Compare the receiver of the method call to item passed in args. The
-	// implementing function should use type assertion to convert the passed
-	// in item. Return true if receiver should be considered a lower
-	// priority than the argument, false otherwise. How you implement
-	// this for your item determines whether the priority queue is a
-	// "min queue" or a "max queue".
-	PQLess(PQItem) bool
+	// Our ordering function
+	less PQLessFunc[T]
 }
 
+// PQLessFunc compares two items on a PriorityQueue. Return true if a
+// should be considered a lower priority than b, false otherwise. How you
+// implement this determines whether the priority queue is a "min queue"
+// or a "max queue".
+type PQLessFunc[T any] func(a, b T) bool
+
 var NotFound = errors.New("item not found in priority queue")
 var PQEmpty = errors.New("priority queue is empty")
 var PQFull = errors.New("priority queue is full")
 
-// Create a new priority queue with max size of maxN
-func NewPriorityQueue(maxN int) *PriorityQueue {
-	return &PriorityQueue{
-		data:   make([]PQItem, maxN+1),
+// Create a new priority queue with max size of maxN, ordered by less.
+// Inserting into a full queue returns PQFull.
+func NewPriorityQueue[T comparable](maxN int, less PQLessFunc[T]) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		data:   make([]T, maxN+1),
 		maxN:   maxN,
-		revMap: make(map[PQItem]int),
+		static: true,
+		revMap: make(map[T]int),
+		less:   less,
+	}
+}
+
+// Create a new priority queue with no max size, ordered by less. The
+// internal storage grows and shrinks as items are inserted and deleted.
+func NewUnboundedPriorityQueue[T comparable](less PQLessFunc[T]) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		// Initially room for one item
+		data:   make([]T, 2),
+		maxN:   1,
+		revMap: make(map[T]int),
+		less:   less,
 	}
 }
 
 // What is the index value of this item?
-func (pq *PriorityQueue) IndexOf(key PQItem) (int, error) {
+func (pq *PriorityQueue[T]) IndexOf(key T) (int, error) {
 	val, ok := pq.revMap[key]
 
 	if !ok {
@@ -57,24 +74,32 @@ func (pq *PriorityQueue) IndexOf(key PQItem) (int, error) {
 }
 
 // Do we contain this item?
-func (pq *PriorityQueue) Contains(key PQItem) bool {
+func (pq *PriorityQueue[T]) Contains(key T) bool {
 	_, ok := pq.revMap[key]
 
 	return ok
 }
 
-// We have changed the value as used by PQLess() for this key. Sink and
+// We have changed the value as used by less() for this key. Sink and
 // swim above/below to be sure value in correct place.
-func (pq *PriorityQueue) IndicateChange(i int) {
+func (pq *PriorityQueue[T]) IndicateChange(i int) {
 	pq.swim(i)
 	pq.sink(i)
 }
 
 // Insert a new value into our priority queue
-func (pq *PriorityQueue) Insert(key PQItem) error {
+func (pq *PriorityQueue[T]) Insert(key T) error {
 
 	if pq.n >= pq.maxN {
-		return PQFull
+
+		if pq.static {
+			// If we are a static queue, we can't resize
+			return PQFull
+		}
+
+		// Otherwise double our size every time we need to resize. Make
+		// sure we grow even if we started at zero.
+		pq.resize(MaxInt(pq.maxN*2, 1))
 	}
 
 	pq.n++
@@ -91,7 +116,7 @@ func (pq *PriorityQueue) Insert(key PQItem) error {
 
 // Delete this item from the priority queue. Returns NotFound if item
 // is not currently in the queue.
-func (pq *PriorityQueue) Delete(key PQItem) error {
+func (pq *PriorityQueue[T]) Delete(key T) error {
 
 	// Get the index or return an error
 	i, err := pq.IndexOf(key)
@@ -106,7 +131,8 @@ func (pq *PriorityQueue) Delete(key PQItem) error {
 	delete(pq.revMap, pq.data[pq.n])
 
 	// Delete the swapped value
-	pq.data[pq.n] = nil
+	var zero T
+	pq.data[pq.n] = zero
 
 	// Decrement our total count of objects
 	pq.n--
@@ -123,22 +149,27 @@ func (pq *PriorityQueue) Delete(key PQItem) error {
 		pq.sink(i)
 	}
 
+	pq.shrink()
+
 	return nil
 }
 
 // GetMax returns the highest priority value without deleting it
-func (pq *PriorityQueue) GetMax() (PQItem, error) {
+func (pq *PriorityQueue[T]) GetMax() (T, error) {
 	if pq.n < 1 {
-		return nil, PQEmpty
+		var zero T
+		return zero, PQEmpty
 	}
 
 	return pq.data[1], nil
 }
 
 // Delete the highest priority item in our queue
-func (pq *PriorityQueue) DelMax() (PQItem, error) {
+func (pq *PriorityQueue[T]) DelMax() (T, error) {
+	var zero T
+
 	if pq.n < 1 {
-		return nil, PQEmpty
+		return zero, PQEmpty
 	}
 
 	// The max item is index 1, get it
@@ -151,7 +182,7 @@ func (pq *PriorityQueue) DelMax() (PQItem, error) {
 	delete(pq.revMap, pq.data[pq.n])
 
 	// Delete the old swapped max
-	pq.data[pq.n] = nil
+	pq.data[pq.n] = zero
 
 	// Our queue is one item shorter now
 	pq.n--
@@ -159,16 +190,18 @@ func (pq *PriorityQueue) DelMax() (PQItem, error) {
 	// Restore heap order
 	pq.sink(1)
 
+	pq.shrink()
+
 	return max, nil
 }
 
 // Starting from k, "swim" higher priority values up toward index 1. This
 // restores heap order from k back to index 1 when a new value is placed at
 // k.
-func (pq *PriorityQueue) swim(k int) {
+func (pq *PriorityQueue[T]) swim(k int) {
 	// While we are not at the top index, and there are still values
 	// out of heap order
-	for k > 1 && pq.data[k/2].PQLess(pq.data[k]) {
+	for k > 1 && pq.less(pq.data[k/2], pq.data[k]) {
 
 		// Swap k with its left child
 		pq.swap(k/2, k)
@@ -181,7 +214,7 @@ func (pq *PriorityQueue) swim(k int) {
 // Starting with k, "sink" lower priority queue values down the tree. This
 // restores heap down from k down to the end of the queue when a new
 // value is inserted at k.
-func (pq *PriorityQueue) sink(k int) {
+func (pq *PriorityQueue[T]) sink(k int) {
 	// While we have a left child of k within the size of our data
 	for 2*k <= pq.n {
 
@@ -194,12 +227,12 @@ func (pq *PriorityQueue) sink(k int) {
 		// be it.
 		j := leftChild
 
-		if rightChild <= pq.n && pq.data[leftChild].PQLess(pq.data[rightChild]) {
+		if rightChild <= pq.n && pq.less(pq.data[leftChild], pq.data[rightChild]) {
 			j = rightChild
 		}
 
 		// j has the largest child, now check against our parent
-		if pq.data[k].PQLess(pq.data[j]) {
+		if pq.less(pq.data[k], pq.data[j]) {
 
 			// If the parent is less, we should swap with j
 			pq.swap(k, j)
@@ -210,8 +243,32 @@ func (pq *PriorityQueue) sink(k int) {
 	}
 }
 
+// If our physical size is quadruple the logical size, resize down to twice
+// logical size. Static queues never shrink.
+func (pq *PriorityQueue[T]) shrink() {
+	if !pq.static && pq.maxN > pq.n*4 {
+		pq.resize(pq.n * 2)
+	}
+}
+
+// Resize our internal data to hold up to newMaxN items
+func (pq *PriorityQueue[T]) resize(newMaxN int) {
+
+	// Silently refuse to resize below size=1
+	if newMaxN < 1 {
+		return
+	}
+
+	// Copy existing items, including the unused data[0]
+	newData := make([]T, newMaxN+1)
+	copy(newData, pq.data[:pq.n+1])
+
+	pq.data = newData
+	pq.maxN = newMaxN
+}
+
 // Swap values at index i and j
-func (pq *PriorityQueue) swap(i, j int) {
+func (pq *PriorityQueue[T]) swap(i, j int) {
 
 	// Swap the reverse map
 	pq.revMap[pq.data[i]], pq.revMap[pq.data[j]] = pq.revMap[pq.data[j]], pq.revMap[pq.data[i]]
@@ -222,16 +279,22 @@ func (pq *PriorityQueue) swap(i, j int) {
 }
 
 // Is our priority queue empty?
-func (pq *PriorityQueue) IsEmpty() bool {
+func (pq *PriorityQueue[T]) IsEmpty() bool {
 	return pq.n < 1
 }
 
-// What is the maximum size we can hold in our priority queue?
-func (pq *PriorityQueue) MaxSize() int {
+// What is the maximum size we can hold in our priority queue? For unbounded
+// queues, this is the current physical size, which changes as we resize.
+func (pq *PriorityQueue[T]) MaxSize() int {
 	return pq.maxN
 }
 
+// Is the priority queue full? Unbounded queues are never full.
+func (pq *PriorityQueue[T]) IsFull() bool {
+	return pq.static && pq.n >= pq.maxN
+}
+
 // What is the current number of items in the priority queue?
-func (pq *PriorityQueue) Size() int {
+func (pq *PriorityQueue[T]) Size() int {
 	return pq.n
 }
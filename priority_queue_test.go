@@ -11,9 +11,8 @@ import (
 
 type PQInt int
 
-func (self PQInt) PQLess(otherItem algo.PQItem) bool {
-	other := otherItem.(PQInt)
-	return self < other
+func pqIntLess(a, b PQInt) bool {
+	return a < b
 }
 
 // Test priority queue by adding/deleting 100k random entries
@@ -22,7 +21,7 @@ func TestPriorityQueueInsertDelete(t *testing.T) {
 
 	numVals := 100000
 
-	pq := algo.NewPriorityQueue(numVals)
+	pq := algo.NewPriorityQueue(numVals, pqIntLess)
 
 	var val, lastVal PQInt
 	for i := 0; i < numVals; i++ {
@@ -37,11 +36,10 @@ func TestPriorityQueueInsertDelete(t *testing.T) {
 
 	lastVal = math.MaxInt32
 	for !pq.IsEmpty() {
-		valItem, err := pq.DelMax()
+		val, err := pq.DelMax()
 		if err != nil {
 			t.Fatal(err)
 		}
-		val = valItem.(PQInt)
 
 		if val > lastVal {
 			t.Fatalf("Expected ordering from highest int to lowest int, but found sequenece %v, %v", lastVal, val)
@@ -56,10 +54,8 @@ type JukeboxSong struct {
 
 // A lower integer value of priority is of higher importance, so use
 // > here.
-func (self *JukeboxSong) PQLess(otherPQ algo.PQItem) bool {
-	other := otherPQ.(*JukeboxSong)
-
-	return self.priority > other.priority
+func jukeboxSongLess(a, b *JukeboxSong) bool {
+	return a.priority > b.priority
 }
 
 // Test other priority queue functionality with a smaller queue
@@ -71,7 +67,7 @@ func TestPriorityQueueOther(t *testing.T) {
 	item5 := &JukeboxSong{"Welcome to the Jungle", 30}
 	item6 := &JukeboxSong{"Highway to Hell", 800}
 
-	pq := algo.NewPriorityQueue(5)
+	pq := algo.NewPriorityQueue(5, jukeboxSongLess)
 	pq.Insert(item1)
 	pq.Insert(item2)
 	pq.Insert(item3)
@@ -84,11 +80,10 @@ func TestPriorityQueueOther(t *testing.T) {
 	}
 
 	// Top song on initial insert should be item4
-	topPQ, err := pq.DelMax()
+	top, err := pq.DelMax()
 	if err != nil {
 		t.Fatal(err)
 	}
-	top := topPQ.(*JukeboxSong)
 	if top != item4 {
 		t.Fatalf("Expected %+v as top item, but got %+v", item4, top)
 	}
@@ -100,11 +95,10 @@ func TestPriorityQueueOther(t *testing.T) {
 	}
 	item3.priority = 5
 	pq.IndicateChange(index)
-	topPQ, err = pq.DelMax()
+	top, err = pq.DelMax()
 	if err != nil {
 		t.Fatal(err)
 	}
-	top = topPQ.(*JukeboxSong)
 	if top != item3 {
 		t.Fatalf("Expected %+v as top item now, but got %+v", item3, top)
 	}
@@ -119,3 +113,42 @@ func TestPriorityQueueOther(t *testing.T) {
 		t.Fatal("Expected empty priority queue")
 	}
 }
+
+// Test that an unbounded priority queue grows and shrinks as needed
+func TestPriorityQueueUnbounded(t *testing.T) {
+	numVals := 100000
+
+	pq := algo.NewUnboundedPriorityQueue(pqIntLess)
+
+	for i := 0; i < numVals; i++ {
+		err := pq.Insert(PQInt(rand.Int31()))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if pq.IsFull() {
+		t.Fatal("Unbounded queue should never be full")
+	}
+
+	if pq.MaxSize() < numVals {
+		t.Fatalf("Expected room for at least %v vals, but found %v", numVals, pq.MaxSize())
+	}
+
+	var lastVal PQInt = math.MaxInt32
+	for !pq.IsEmpty() {
+		val, err := pq.DelMax()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if val > lastVal {
+			t.Fatalf("Expected ordering from highest int to lowest int, but found sequenece %v, %v", lastVal, val)
+		}
+		lastVal = val
+
+		if pq.Size() > 0 && pq.MaxSize() > pq.Size()*4 {
+			t.Fatalf("Expected queue to shrink, but found size %v with max size %v", pq.Size(), pq.MaxSize())
+		}
+	}
+}
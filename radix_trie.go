@@ -0,0 +1,285 @@
+package algo
+
+import (
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// RadixTrie is a node in a compressed trie. Unlike Trie, any chain of nodes
+// with only one child each is collapsed into a single node whose Label holds
+// all of their letters, so it takes far fewer nodes to hold the same words.
+type RadixTrie struct {
+	// The letters on the edge leading to this node
+	Label string
+
+	// Value is a bitmask, the same as Trie.Value. A zero value must
+	// represent a non-word.
+	Value int64
+
+	// How many bytes of a word come before Label
+	depth int
+
+	// A pointer to the next sibling of this node
+	Sibling *RadixTrie
+
+	// A pointer to the first child of this node
+	Child *RadixTrie
+}
+
+// NewRadixTrie creates a new radix trie root with 0 as the value.
+func NewRadixTrie() *RadixTrie {
+	return &RadixTrie{}
+}
+
+// commonPrefix returns how many bytes a and b have in common at the start,
+// without splitting a rune in half
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	for i > 0 && i < len(a) && !utf8.RuneStart(a[i]) {
+		i--
+	}
+
+	return i
+}
+
+// findChild finds the node one level below t whose label starts with the
+// same letter as word or returns nil. Returns the node and how many bytes
+// its label has in common with word.
+func (t *RadixTrie) findChild(word string) (*RadixTrie, int) {
+	child := t.Child
+
+	// No two siblings start with the same letter, so the first one with
+	// anything in common is the only one
+	for child != nil {
+		n := commonPrefix(child.Label, word)
+		if n > 0 {
+			return child, n
+		}
+
+		child = child.Sibling
+	}
+
+	return nil, 0
+}
+
+// appendChild adds child to the end of the children list of t. Returns how
+// many siblings the node has.
+func (t *RadixTrie) appendChild(child *RadixTrie) int {
+	var siblings int
+
+	if t.Child == nil {
+		t.Child = child
+		return siblings
+	}
+
+	tail := t.Child
+	for tail.Sibling != nil {
+		tail = tail.Sibling
+		siblings++
+	}
+
+	tail.Sibling = child
+
+	return siblings
+}
+
+// split breaks t in two after the first n bytes of its label. t keeps its
+// place in the trie and the rest of its label, value and children move to a
+// new node below it.
+func (t *RadixTrie) split(n int) {
+	child := &RadixTrie{
+		Label: t.Label[n:],
+		Value: t.Value,
+		depth: t.depth + n,
+		Child: t.Child,
+	}
+
+	t.Label = t.Label[:n]
+	t.Value = 0
+	t.Child = child
+}
+
+// Add a word to the trie. Returns how many new nodes were created, and the
+// maximum number of siblings a node has.
+func (t *RadixTrie) Add(word string, value int64) (int, int) {
+	var newNodes, maxSiblings int
+
+	node := t
+	rest := word
+
+	for len(rest) > 0 {
+		child, n := node.findChild(rest)
+
+		// Nothing shares a prefix, so the rest of the word becomes a
+		// single new node. Copy it so we don't hold onto the caller's
+		// string.
+		if child == nil {
+			child = &RadixTrie{
+				Label: strings.Clone(rest),
+				depth: len(word) - len(rest),
+			}
+			newNodes++
+
+			siblings := node.appendChild(child)
+			if siblings > maxSiblings {
+				maxSiblings = siblings
+			}
+
+			node = child
+			break
+		}
+
+		// The word leaves this label partway through, so split the
+		// node where they differ
+		if n < len(child.Label) {
+			child.split(n)
+			newNodes++
+		}
+
+		node = child
+		rest = rest[n:]
+	}
+
+	// Set new value of this node by running OR on existing value
+	node.Value = node.Value | value
+
+	return newNodes, maxSiblings
+}
+
+// Exists returns a boolean indicating whether this word exists or not in our
+// trie. It also returns the node where the word ends, which may be partway
+// through the node's label, or nil if no word starts with this one.
+func (t *RadixTrie) Exists(word string) (bool, *RadixTrie) {
+	node := t
+	rest := word
+
+	for len(rest) > 0 {
+		child, n := node.findChild(rest)
+		if child == nil {
+			return false, nil
+		}
+
+		if n < len(child.Label) {
+			// The word ends inside this label, so it's a prefix
+			// of something but not a word itself
+			if n == len(rest) {
+				return false, child
+			}
+
+			return false, nil
+		}
+
+		node = child
+		rest = rest[n:]
+	}
+
+	return node.Value > 0, node
+}
+
+type RadixCompletion struct {
+	Word string
+	Node *RadixTrie
+}
+
+// FindCompletions does a breadth-first search below this trie node, and
+// finds up to max completed words under it. word should be the one passed
+// to Exists to find this node. If word ends partway through the label of
+// this node, the node itself may be a completion.
+func (t *RadixTrie) FindCompletions(word string, maxWords int) []RadixCompletion {
+	var (
+		child       *RadixTrie
+		childWord   string
+		completions []RadixCompletion
+		tmp         interface{}
+	)
+
+	if maxWords < 1 {
+		return completions
+	}
+
+	// Finish the word if it stops inside our label
+	if t.depth <= len(word) && len(word) < t.depth+len(t.Label) {
+		word = word[:t.depth] + t.Label
+
+		if t.Value > 0 {
+			completions = append(completions, RadixCompletion{
+				Word: word,
+				Node: t,
+			})
+		}
+
+		if len(completions) >= maxWords {
+			return completions
+		}
+	}
+
+	wordQ := NewQueue()
+	trieQ := NewQueue()
+	wordQ.Enqueue(word)
+	trieQ.Enqueue(t)
+
+	// While we still have nodes in our queue
+	for !wordQ.IsEmpty() && !trieQ.IsEmpty() {
+
+		// Get the word and trie node off the queue
+		tmp, _ = wordQ.Dequeue()
+		word = tmp.(string)
+		tmp, _ = trieQ.Dequeue()
+		child = tmp.(*RadixTrie).Child
+
+		// Check for children that complete a word
+		for child != nil {
+			childWord = word + child.Label
+
+			// If it's a word, add it to our words
+			if child.Value > 0 {
+				completion := RadixCompletion{
+					Word: childWord,
+					Node: child,
+				}
+				completions = append(completions, completion)
+			}
+
+			// If we have enough words, then stop
+			if len(completions) >= maxWords {
+				return completions
+			}
+
+			// Add child to queues to process its children
+			wordQ.Enqueue(childWord)
+			trieQ.Enqueue(child)
+
+			// Try the next sibling
+			child = child.Sibling
+		}
+	}
+
+	return completions
+}
+
+// Stats counts the nodes and words under this node, including the node
+// itself, and estimates how much memory they use
+func (t *RadixTrie) Stats() TrieStats {
+	var stats TrieStats
+
+	t.stats(&stats)
+
+	return stats
+}
+
+func (t *RadixTrie) stats(stats *TrieStats) {
+	stats.Nodes++
+	stats.Bytes += int(unsafe.Sizeof(*t)) + len(t.Label)
+	if t.Value > 0 {
+		stats.Words++
+	}
+
+	for child := t.Child; child != nil; child = child.Sibling {
+		child.stats(stats)
+	}
+}
@@ -0,0 +1,126 @@
+package algo_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/brnstz/algo"
+)
+
+// Load every word in filename into both kinds of trie
+func loadTries(filename string, t *testing.T) (*algo.Trie, *algo.RadixTrie, []string) {
+	var (
+		word  string
+		words []string
+	)
+
+	trie := algo.NewTrie()
+	radix := algo.NewRadixTrie()
+
+	fh, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+
+	for {
+		_, err := fmt.Fscan(fh, &word)
+		if err == io.EOF {
+			break
+		}
+
+		trie.Add(word, 1)
+		radix.Add(word, 1)
+		words = append(words, word)
+	}
+
+	return trie, radix, words
+}
+
+// Every prefix of every word should give the same answers in both tries
+func checkSameTries(t *testing.T, trie *algo.Trie, radix *algo.RadixTrie, words []string) {
+	seen := map[string]bool{}
+
+	for _, word := range words {
+		var ends []int
+		for i := range word {
+			ends = append(ends, i)
+		}
+		ends = append(ends, len(word))
+
+		for _, i := range ends {
+			prefix := word[:i]
+			if seen[prefix] {
+				continue
+			}
+			seen[prefix] = true
+
+			trieExists, trieNode := trie.Exists(prefix)
+			radixExists, radixNode := radix.Exists(prefix)
+
+			if trieExists != radixExists {
+				t.Fatalf("%q exists in trie: %v, radix: %v", prefix, trieExists, radixExists)
+			}
+
+			var expected, actual []string
+			for _, c := range trieNode.FindCompletions(prefix, len(words)) {
+				expected = append(expected, c.Word)
+			}
+			for _, c := range radixNode.FindCompletions(prefix, len(words)) {
+				actual = append(actual, c.Word)
+			}
+			sort.Strings(expected)
+			sort.Strings(actual)
+
+			if fmt.Sprint(expected) != fmt.Sprint(actual) {
+				t.Fatalf("%q completions in trie: %v, radix: %v", prefix, expected, actual)
+			}
+		}
+	}
+}
+
+func TestRadixTrie(t *testing.T) {
+	for _, filename := range []string{"data/words3.txt", "data/tale.txt"} {
+		trie, radix, words := loadTries(filename, t)
+
+		checkSameTries(t, trie, radix, words)
+
+		if exists, node := radix.Exists("qqqqq"); exists || node != nil {
+			t.Fatalf("Found word not in trie")
+		}
+
+		trieStats := trie.Stats()
+		radixStats := radix.Stats()
+
+		if trieStats.Words != radixStats.Words {
+			t.Fatalf("Expected %v words in radix trie but got %v", trieStats.Words, radixStats.Words)
+		}
+		if radixStats.Nodes > trieStats.Nodes {
+			t.Fatalf("Radix trie has more nodes than trie: %v > %v", radixStats.Nodes, trieStats.Nodes)
+		}
+
+		t.Logf("%v: trie %+v, radix %+v", filename, trieStats, radixStats)
+	}
+}
+
+// Words that only differ partway through a rune shouldn't split it
+func TestRadixTrieUnicode(t *testing.T) {
+	words := []string{"café", "cafè", "caf", "日本語", "日本", "日曜日"}
+
+	trie := algo.NewTrie()
+	radix := algo.NewRadixTrie()
+	for _, word := range words {
+		trie.Add(word, 1)
+		radix.Add(word, 1)
+	}
+
+	checkSameTries(t, trie, radix, words)
+
+	completions := radix.FindCompletions("", len(words))
+	if len(completions) != len(words) {
+		t.Fatalf("Expected %v completions but got %v", len(words), len(completions))
+	}
+}
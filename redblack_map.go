@@ -0,0 +1,95 @@
+package algo
+
+import (
+	"cmp"
+	"iter"
+)
+
+// RedBlackMap is an ordered map from keys to values, stored on a
+// RedBlackTree. Putting an existing key replaces its value instead of
+// counting a dupe.
+type RedBlackMap[K cmp.Ordered, V any] struct {
+	tree RedBlackTree
+}
+
+// A key/value pair stored as the Value of a Node. Entries are compared by
+// key only.
+type mapEntry[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+}
+
+// Use cmp rather than < and == so that a NaN key sorts before every other
+// float and equals itself, like any other key
+func (e mapEntry[K, V]) Less(other NodeValue) bool {
+	return cmp.Less(e.key, other.(mapEntry[K, V]).key)
+}
+
+func (e mapEntry[K, V]) Equals(other NodeValue) bool {
+	return cmp.Compare(e.key, other.(mapEntry[K, V]).key) == 0
+}
+
+// Create a new, empty map
+func NewRedBlackMap[K cmp.Ordered, V any]() *RedBlackMap[K, V] {
+	return &RedBlackMap[K, V]{}
+}
+
+// Put value into the map at key, replacing any existing value
+func (m *RedBlackMap[K, V]) Put(key K, value V) {
+	entry := mapEntry[K, V]{key: key, value: value}
+
+	// If the key is already there, update it in place
+	n := m.tree.find(m.tree.Root, entry)
+	if n != nil {
+		n.Value = entry
+		return
+	}
+
+	m.tree.Put(entry)
+}
+
+// Get the value at key. Returns false if key is not in the map.
+func (m *RedBlackMap[K, V]) Get(key K) (V, bool) {
+	n := m.tree.find(m.tree.Root, mapEntry[K, V]{key: key})
+	if n == nil {
+		var zero V
+		return zero, false
+	}
+
+	return n.Value.(mapEntry[K, V]).value, true
+}
+
+// Delete key from the map. Returns false if key is not in the map.
+func (m *RedBlackMap[K, V]) Delete(key K) bool {
+	return m.tree.Delete(mapEntry[K, V]{key: key})
+}
+
+// How many keys are in the map?
+func (m *RedBlackMap[K, V]) Len() int {
+	return m.tree.Size()
+}
+
+// Keys returns an iterator over every key in the map, from smallest to
+// largest
+func (m *RedBlackMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for v := range m.tree.All() {
+			if !yield(v.(mapEntry[K, V]).key) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over every key and value in the map, from
+// smallest to largest key
+func (m *RedBlackMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for v := range m.tree.All() {
+			entry := v.(mapEntry[K, V])
+			if !yield(entry.key, entry.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package algo_test
+
+import (
+	"github.com/brnstz/algo"
+
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"testing"
+)
+
+// Count words in the tale, then check the counts in the map
+func TestRedBlackMap(t *testing.T) {
+	m := algo.NewRedBlackMap[string, int]()
+	counts := map[string]int{}
+
+	fh, err := os.Open("data/tale.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+
+	var word string
+	for {
+		_, err := fmt.Fscan(fh, &word)
+		if err == io.EOF {
+			break
+		}
+
+		count, _ := m.Get(word)
+		m.Put(word, count+1)
+		counts[word]++
+	}
+
+	if m.Len() != len(counts) {
+		t.Fatalf("Expected %v keys but got %v", len(counts), m.Len())
+	}
+
+	if _, ok := m.Get("slfkjkldsf"); ok {
+		t.Fatal("Found unexpected word")
+	}
+
+	lastWord := ""
+	for word, count := range m.All() {
+		if word <= lastWord {
+			t.Fatalf("Expected ordering from lowest to highest key, but found sequence %v, %v", lastWord, word)
+		}
+		lastWord = word
+
+		if count != counts[word] {
+			t.Fatalf("Expected count of %v for %v but got %v", counts[word], word, count)
+		}
+	}
+
+	if !m.Delete("goodfellowship") || m.Delete("goodfellowship") {
+		t.Fatal("Expected to delete exactly once")
+	}
+
+	keys := 0
+	for word := range m.Keys() {
+		if word == "goodfellowship" {
+			t.Fatal("Found deleted word")
+		}
+		keys++
+	}
+
+	if keys != len(counts)-1 {
+		t.Fatalf("Expected %v keys but got %v", len(counts)-1, keys)
+	}
+}
+
+// NaN isn't == to itself, but it should still be one key
+func TestRedBlackMapNaN(t *testing.T) {
+	m := algo.NewRedBlackMap[float64, string]()
+
+	m.Put(1, "one")
+	m.Put(math.NaN(), "nan")
+	m.Put(math.NaN(), "still nan")
+	m.Put(-1, "minus one")
+
+	if m.Len() != 3 {
+		t.Fatalf("Expected 3 keys but got %v", m.Len())
+	}
+
+	value, ok := m.Get(math.NaN())
+	if !ok || value != "still nan" {
+		t.Fatalf("Expected still nan but got %q, %v", value, ok)
+	}
+
+	// NaN sorts first, like cmp.Compare
+	var keys []string
+	for k := range m.Keys() {
+		keys = append(keys, fmt.Sprint(k))
+	}
+	if fmt.Sprint(keys) != "[NaN -1 1]" {
+		t.Fatalf("Unexpected keys %v", keys)
+	}
+
+	if !m.Delete(math.NaN()) || m.Len() != 2 {
+		t.Fatal("Expected to delete NaN")
+	}
+	if _, ok = m.Get(math.NaN()); ok {
+		t.Fatal("NaN is still there")
+	}
+}
@@ -2,6 +2,7 @@ package algo
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 )
 
@@ -14,6 +15,9 @@ const (
 	black color = false
 )
 
+var EmptyTree = errors.New("tree is empty")
+var RankOutOfRange = errors.New("rank is out of range")
+
 // Implement this type to store into Node.Value
 type NodeValue interface {
 	Less(NodeValue) bool
@@ -23,6 +27,19 @@ type NodeValue interface {
 // Pointer to root of the tree
 type RedBlackTree struct {
 	Root *Node
+
+	// Converts values to and from bytes. Only needed to save and load the
+	// tree with MarshalBinary, WriteTo and friends.
+	Codec NodeValueCodec
+
+	// When non-zero, nodes with a different version are shared with
+	// other trees and must be copied before changing them. See
+	// PersistentRedBlackTree.
+	version uint64
+
+	// When set, called to recalculate Node.meta whenever the children of
+	// a node change. See IntervalTree.
+	augment func(*Node)
 }
 
 // A node on the tree
@@ -44,6 +61,13 @@ type Node struct {
 
 	// Value this node holds
 	Value NodeValue
+
+	// Version of the tree that created this node
+	version uint64
+
+	// Extra data about the subtree at this node, kept up to date by
+	// RedBlackTree.augment
+	meta interface{}
 }
 
 // Create a red node with this value
@@ -63,7 +87,7 @@ func (t *RedBlackTree) Put(v NodeValue) {
 	t.Root = t.put(t.Root, v)
 
 	// Root is always black
-	t.Root.Color = black
+	t.setRootColor(black)
 }
 
 // Recursively put v into the tree, returning potential replacement
@@ -72,9 +96,14 @@ func (t *RedBlackTree) put(n *Node, v NodeValue) *Node {
 
 	// If node is nil, we've found a place for replacement.
 	if n == nil {
-		return NewNode(v)
+		n = NewNode(v)
+		n.version = t.version
+		t.update(n)
+		return n
 	}
 
+	n = t.own(n)
+
 	// If v and node equal, then just increment count and return
 	// same parent.
 	if v.Equals(n.Value) {
@@ -89,6 +118,249 @@ func (t *RedBlackTree) put(n *Node, v NodeValue) *Node {
 		n.Right = t.put(n.Right, v)
 	}
 
+	return t.balance(n)
+}
+
+// Delete one copy of value v from the tree. Returns false if v is not in
+// the tree.
+func (t *RedBlackTree) Delete(v NodeValue) bool {
+	n := t.find(t.Root, v)
+	if n == nil {
+		return false
+	}
+
+	// If we have dupes, just decrement the count
+	if n.ValueCount > 1 {
+		t.Root = t.decrement(t.Root, v)
+		return true
+	}
+
+	// If both children of root are black, set root to red so we have a
+	// red link to carry down the tree
+	if !t.Root.Left.isRed() && !t.Root.Right.isRed() {
+		t.setRootColor(red)
+	}
+
+	t.Root = t.delete(t.Root, v)
+
+	// Root is always black
+	if t.Root != nil {
+		t.setRootColor(black)
+	}
+
+	return true
+}
+
+// Delete one copy of the smallest value in the tree and return it
+func (t *RedBlackTree) DeleteMin() (NodeValue, error) {
+	if t.Root == nil {
+		return nil, EmptyTree
+	}
+
+	min := t.min(t.Root)
+
+	// If we have dupes, just decrement the count
+	if min.ValueCount > 1 {
+		t.Root = t.decrement(t.Root, min.Value)
+		return min.Value, nil
+	}
+
+	if !t.Root.Left.isRed() && !t.Root.Right.isRed() {
+		t.setRootColor(red)
+	}
+
+	t.Root = t.deleteMin(t.Root)
+
+	if t.Root != nil {
+		t.setRootColor(black)
+	}
+
+	return min.Value, nil
+}
+
+// Delete one copy of the largest value in the tree and return it
+func (t *RedBlackTree) DeleteMax() (NodeValue, error) {
+	if t.Root == nil {
+		return nil, EmptyTree
+	}
+
+	max := t.max(t.Root)
+
+	// If we have dupes, just decrement the count
+	if max.ValueCount > 1 {
+		t.Root = t.decrement(t.Root, max.Value)
+		return max.Value, nil
+	}
+
+	if !t.Root.Left.isRed() && !t.Root.Right.isRed() {
+		t.setRootColor(red)
+	}
+
+	t.Root = t.deleteMax(t.Root)
+
+	if t.Root != nil {
+		t.setRootColor(black)
+	}
+
+	return max.Value, nil
+}
+
+// Recursively delete the node holding v under n, returning the potential
+// replacement for n. v must be in the tree.
+func (t *RedBlackTree) delete(n *Node, v NodeValue) *Node {
+	n = t.own(n)
+
+	if v.Less(n.Value) {
+		// Make sure we don't delete a 2-node on the left by borrowing a
+		// red link
+		if !n.Left.isRed() && !n.Left.Left.isRed() {
+			n = t.moveRedLeft(n)
+		}
+
+		n.Left = t.delete(n.Left, v)
+
+	} else {
+		// Lean red links right so we can carry them down the right side
+		if n.Left.isRed() {
+			n = t.rotateRight(n)
+		}
+
+		// Found it at the bottom of the tree, just remove it
+		if v.Equals(n.Value) && n.Right == nil {
+			return nil
+		}
+
+		// Make sure we don't delete a 2-node on the right
+		if !n.Right.isRed() && !n.Right.Left.isRed() {
+			n = t.moveRedRight(n)
+		}
+
+		if v.Equals(n.Value) {
+			// Found it in the middle of the tree. Replace it with its
+			// successor and delete the successor instead.
+			min := t.min(n.Right)
+			n.Value = min.Value
+			n.ValueCount = min.ValueCount
+			n.Right = t.deleteMin(n.Right)
+
+		} else {
+			n.Right = t.delete(n.Right, v)
+		}
+	}
+
+	return t.balance(n)
+}
+
+// Recursively delete the smallest node under n, returning the potential
+// replacement for n
+func (t *RedBlackTree) deleteMin(n *Node) *Node {
+	// Smallest node is the one with no left child
+	if n.Left == nil {
+		return nil
+	}
+
+	n = t.own(n)
+
+	if !n.Left.isRed() && !n.Left.Left.isRed() {
+		n = t.moveRedLeft(n)
+	}
+
+	n.Left = t.deleteMin(n.Left)
+
+	return t.balance(n)
+}
+
+// Recursively delete the largest node under n, returning the potential
+// replacement for n
+func (t *RedBlackTree) deleteMax(n *Node) *Node {
+	n = t.own(n)
+
+	if n.Left.isRed() {
+		n = t.rotateRight(n)
+	}
+
+	// Largest node is the one with no right child
+	if n.Right == nil {
+		return nil
+	}
+
+	if !n.Right.isRed() && !n.Right.Left.isRed() {
+		n = t.moveRedRight(n)
+	}
+
+	n.Right = t.deleteMax(n.Right)
+
+	return t.balance(n)
+}
+
+// Assuming n is red and both n.Left and n.Left.Left are black, make n.Left
+// or one of its children red
+func (t *RedBlackTree) moveRedLeft(n *Node) *Node {
+	t.flipColors(n)
+
+	// If our right sibling can spare a red link, borrow it
+	if n.Right.Left.isRed() {
+		n.Right = t.rotateRight(n.Right)
+		n = t.rotateLeft(n)
+		t.flipColors(n)
+	}
+
+	return n
+}
+
+// Assuming n is red and both n.Right and n.Right.Left are black, make
+// n.Right or one of its children red
+func (t *RedBlackTree) moveRedRight(n *Node) *Node {
+	t.flipColors(n)
+
+	// If our left sibling can spare a red link, borrow it
+	if n.Left.Left.isRed() {
+		n = t.rotateRight(n)
+		t.flipColors(n)
+	}
+
+	return n
+}
+
+// Recursively decrement the count of v under n, returning potential
+// replacement for n. v must be in the tree.
+func (t *RedBlackTree) decrement(n *Node, v NodeValue) *Node {
+	n = t.own(n)
+
+	if v.Equals(n.Value) {
+		n.ValueCount--
+	} else if v.Less(n.Value) {
+		n.Left = t.decrement(n.Left, v)
+	} else {
+		n.Right = t.decrement(n.Right, v)
+	}
+
+	return n
+}
+
+// Smallest node under n
+func (t *RedBlackTree) min(n *Node) *Node {
+	for n.Left != nil {
+		n = n.Left
+	}
+
+	return n
+}
+
+// Largest node under n
+func (t *RedBlackTree) max(n *Node) *Node {
+	for n.Right != nil {
+		n = n.Right
+	}
+
+	return n
+}
+
+// Restore red-black invariants at n on the way back up the tree, returning
+// potential replacement for n
+func (t *RedBlackTree) balance(n *Node) *Node {
+	n = t.own(n)
+
 	// If n is not left-leaning, rotateLeft to make it so.
 	if !n.Left.isRed() && n.Right.isRed() {
 		n = t.rotateLeft(n)
@@ -104,13 +376,17 @@ func (t *RedBlackTree) put(n *Node, v NodeValue) *Node {
 		t.flipColors(n)
 	}
 
-	n.NodeCount = n.Left.nodeCountZeroNil() + n.Right.nodeCountZeroNil() + 1
+	t.update(n)
 
 	return n
 }
 
-// Flip colors to fix two red links at same node
+// Flip colors to fix two red links at same node. n must already be owned
+// by this tree.
 func (t *RedBlackTree) flipColors(n *Node) {
+	n.Left = t.own(n.Left)
+	n.Right = t.own(n.Right)
+
 	n.Color = !n.Color
 
 	n.Left.Color = !n.Left.Color
@@ -140,8 +416,10 @@ func (t *RedBlackTree) rotateLeft(op *Node) *Node {
 	//                      A   E
 	//
 
+	op = t.own(op)
+
 	// The new parent is the right child
-	np := op.Right
+	np := t.own(op.Right)
 
 	// The original parent's right child now points to the
 	// original right child's left child.
@@ -156,6 +434,10 @@ func (t *RedBlackTree) rotateLeft(op *Node) *Node {
 	// Left link is now red
 	np.Left.Color = red
 
+	// Old parent is now below new parent, so recalculate it first
+	t.update(op)
+	t.update(np)
+
 	// Return new parent
 	return np
 }
@@ -184,8 +466,10 @@ func (t *RedBlackTree) rotateRight(op *Node) *Node {
 	//                         E     H
 	//
 
+	op = t.own(op)
+
 	// The new parent is the left child
-	np := op.Left
+	np := t.own(op.Left)
 
 	// The original parent's left child now points to the new parent's
 	// right child.
@@ -200,25 +484,29 @@ func (t *RedBlackTree) rotateRight(op *Node) *Node {
 	// Right link is now red
 	np.Right.Color = red
 
+	// Old parent is now below new parent, so recalculate it first
+	t.update(op)
+	t.update(np)
+
 	return np
 }
 
 // Can we find value v in the tree?
 func (t *RedBlackTree) Find(v NodeValue) bool {
-	return t.find(t.Root, v)
+	return t.find(t.Root, v) != nil
 }
 
-// Helper function for Find
-func (t *RedBlackTree) find(n *Node, v NodeValue) bool {
+// Helper function for Find. Returns the node holding v or nil.
+func (t *RedBlackTree) find(n *Node, v NodeValue) *Node {
 
 	// We reached a nil node, which means we can't find our value
 	if n == nil {
-		return false
+		return nil
 	}
 
-	// We found it, return true
+	// We found it, return it
 	if v.Equals(n.Value) {
-		return true
+		return n
 	}
 
 	if v.Less(n.Value) {
@@ -232,6 +520,145 @@ func (t *RedBlackTree) find(n *Node, v NodeValue) bool {
 	}
 }
 
+// How many distinct values are in the tree?
+func (t *RedBlackTree) Size() int {
+	return t.Root.nodeCountZeroNil()
+}
+
+// Smallest value in the tree
+func (t *RedBlackTree) Min() (NodeValue, error) {
+	if t.Root == nil {
+		return nil, EmptyTree
+	}
+
+	return t.min(t.Root).Value, nil
+}
+
+// Largest value in the tree
+func (t *RedBlackTree) Max() (NodeValue, error) {
+	if t.Root == nil {
+		return nil, EmptyTree
+	}
+
+	return t.max(t.Root).Value, nil
+}
+
+// Floor returns the largest value in the tree less than or equal to v.
+// Returns false if there is no such value.
+func (t *RedBlackTree) Floor(v NodeValue) (NodeValue, bool) {
+	var floor NodeValue
+
+	n := t.Root
+	for n != nil {
+		if v.Equals(n.Value) {
+			return n.Value, true
+		}
+
+		if v.Less(n.Value) {
+			// Floor must be on the left
+			n = n.Left
+		} else {
+			// This node is a candidate, but there may be a bigger one on
+			// the right
+			floor = n.Value
+			n = n.Right
+		}
+	}
+
+	return floor, floor != nil
+}
+
+// Ceiling returns the smallest value in the tree greater than or equal to
+// v. Returns false if there is no such value.
+func (t *RedBlackTree) Ceiling(v NodeValue) (NodeValue, bool) {
+	var ceiling NodeValue
+
+	n := t.Root
+	for n != nil {
+		if v.Equals(n.Value) {
+			return n.Value, true
+		}
+
+		if v.Less(n.Value) {
+			// This node is a candidate, but there may be a smaller one on
+			// the left
+			ceiling = n.Value
+			n = n.Left
+		} else {
+			// Ceiling must be on the right
+			n = n.Right
+		}
+	}
+
+	return ceiling, ceiling != nil
+}
+
+// Rank returns the number of distinct values in the tree that are less
+// than v. v does not need to be in the tree.
+func (t *RedBlackTree) Rank(v NodeValue) int {
+	rank := 0
+
+	n := t.Root
+	for n != nil {
+		if v.Equals(n.Value) {
+			// Everything on the left is less
+			return rank + n.Left.nodeCountZeroNil()
+		}
+
+		if v.Less(n.Value) {
+			n = n.Left
+		} else {
+			// This node and everything on its left is less
+			rank += n.Left.nodeCountZeroNil() + 1
+			n = n.Right
+		}
+	}
+
+	return rank
+}
+
+// Select returns the value with rank k, that is, the value that has
+// exactly k distinct values less than it. Returns RankOutOfRange unless
+// 0 <= k < Size().
+func (t *RedBlackTree) Select(k int) (NodeValue, error) {
+	if k < 0 || k >= t.Size() {
+		return nil, RankOutOfRange
+	}
+
+	n := t.Root
+	for {
+		left := n.Left.nodeCountZeroNil()
+
+		if k < left {
+			// It's on the left
+			n = n.Left
+		} else if k > left {
+			// It's on the right, skip this node and everything on its left
+			k -= left + 1
+			n = n.Right
+		} else {
+			return n.Value, nil
+		}
+	}
+}
+
+// CountRange returns the number of distinct values in the tree between lo
+// and hi, inclusive
+func (t *RedBlackTree) CountRange(lo, hi NodeValue) int {
+	if hi.Less(lo) {
+		return 0
+	}
+
+	count := t.Rank(hi) - t.Rank(lo)
+
+	// Rank doesn't count hi itself
+	if t.Find(hi) {
+		count++
+	}
+
+	return count
+}
+
 // What is the height of the tree?
 func (t *RedBlackTree) Height() int {
 	return t.height(t.Root)
@@ -311,6 +738,36 @@ func (t *RedBlackTree) BFSString() string {
 	return out.String()
 }
 
+// Recalculate the node count of n, and its metadata if we have any, from
+// its children
+func (t *RedBlackTree) update(n *Node) {
+	n.NodeCount = n.Left.nodeCountZeroNil() + n.Right.nodeCountZeroNil() + 1
+
+	if t.augment != nil {
+		t.augment(n)
+	}
+}
+
+// Set the color of the root node
+func (t *RedBlackTree) setRootColor(c color) {
+	t.Root = t.own(t.Root)
+	t.Root.Color = c
+}
+
+// Return a version of n that is safe to change. For a regular tree this is
+// always n itself. For a tree with a version, any node from an older
+// version is copied so that the older version is left intact.
+func (t *RedBlackTree) own(n *Node) *Node {
+	if t.version == 0 || n == nil || n.version == t.version {
+		return n
+	}
+
+	c := *n
+	c.version = t.version
+
+	return &c
+}
+
 // Private helper function to check that node is defined and red
 func (n *Node) isRed() bool {
 	if n != nil && n.Color == red {
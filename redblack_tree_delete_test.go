@@ -0,0 +1,151 @@
+package algo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Implement NodeValue interface for ints
+type intNode int
+
+func (i intNode) Less(other_ NodeValue) bool {
+	return i < other_.(intNode)
+}
+
+func (i intNode) Equals(other_ NodeValue) bool {
+	return i == other_.(intNode)
+}
+
+// Check every red-black invariant below n, returning its black height.
+// lo and hi bound the values allowed under n.
+func checkRedBlack(t *testing.T, n *Node, lo, hi NodeValue) int {
+	if n == nil {
+		return 0
+	}
+
+	if lo != nil && !lo.Less(n.Value) {
+		t.Fatalf("%v is out of order, should be greater than %v", n.Value, lo)
+	}
+	if hi != nil && !n.Value.Less(hi) {
+		t.Fatalf("%v is out of order, should be less than %v", n.Value, hi)
+	}
+
+	if n.Right.isRed() {
+		t.Fatalf("%v has a red right link", n.Value)
+	}
+
+	if n.isRed() && n.Left.isRed() {
+		t.Fatalf("%v has two red links in a row", n.Value)
+	}
+
+	if n.ValueCount < 1 {
+		t.Fatalf("%v has value count %v", n.Value, n.ValueCount)
+	}
+
+	count := n.Left.nodeCountZeroNil() + n.Right.nodeCountZeroNil() + 1
+	if n.NodeCount != count {
+		t.Fatalf("%v has node count %v but should be %v", n.Value, n.NodeCount, count)
+	}
+
+	left := checkRedBlack(t, n.Left, lo, n.Value)
+	right := checkRedBlack(t, n.Right, n.Value, hi)
+	if left != right {
+		t.Fatalf("%v is not black balanced, %v != %v", n.Value, left, right)
+	}
+
+	if n.isRed() {
+		return left
+	}
+
+	return left + 1
+}
+
+// Check the whole tree
+func checkTree(t *testing.T, tree *RedBlackTree) {
+	if tree.Root.isRed() {
+		t.Fatal("Root is red")
+	}
+
+	checkRedBlack(t, tree.Root, nil, nil)
+}
+
+// Put random values, then delete them in random order, checking
+// invariants along the way
+func TestRedBlackDelete(t *testing.T) {
+	numVals := 2000
+
+	tree := RedBlackTree{}
+	counts := map[intNode]int{}
+
+	for i := 0; i < numVals; i++ {
+		v := intNode(rand.Intn(numVals / 2))
+		tree.Put(v)
+		counts[v]++
+	}
+	checkTree(t, &tree)
+
+	if tree.Delete(intNode(-1)) {
+		t.Fatal("Deleted value not in tree")
+	}
+
+	for _, v := range rand.Perm(numVals / 2) {
+		for counts[intNode(v)] > 0 {
+			if !tree.Delete(intNode(v)) {
+				t.Fatalf("Can't delete %v", v)
+			}
+			counts[intNode(v)]--
+			checkTree(t, &tree)
+		}
+
+		if tree.Find(intNode(v)) {
+			t.Fatalf("Found deleted value %v", v)
+		}
+	}
+
+	if tree.Root != nil {
+		t.Fatal("Expected empty tree")
+	}
+}
+
+// Put values and delete them from both ends
+func TestRedBlackDeleteMinMax(t *testing.T) {
+	numVals := 1000
+
+	tree := RedBlackTree{}
+	for _, v := range rand.Perm(numVals) {
+		tree.Put(intNode(v))
+	}
+
+	// Add a dupe of the smallest value
+	tree.Put(intNode(0))
+
+	for _, expected := range []intNode{0, 0, 1, 2} {
+		v, err := tree.DeleteMin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != expected {
+			t.Fatalf("Expected %v as min but got %v", expected, v)
+		}
+		checkTree(t, &tree)
+	}
+
+	for i := numVals - 1; i > 2; i-- {
+		v, err := tree.DeleteMax()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != intNode(i) {
+			t.Fatalf("Expected %v as max but got %v", i, v)
+		}
+		checkTree(t, &tree)
+	}
+
+	if _, err := tree.DeleteMax(); err != EmptyTree {
+		t.Fatal("Expected empty tree")
+	}
+
+	if _, err := tree.DeleteMin(); err != EmptyTree {
+		t.Fatal("Expected empty tree")
+	}
+}
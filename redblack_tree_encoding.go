@@ -0,0 +1,325 @@
+package algo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+var NoCodec = errors.New("tree has no codec for its values")
+var NotSorted = errors.New("values are not in sorted order")
+var BadSnapshot = errors.New("data is not a red-black tree snapshot")
+
+// Every snapshot starts with these bytes
+var snapshotMagic = [4]byte{'r', 'b', 't', 1}
+
+// Implement this interface to convert NodeValues to and from bytes, so a
+// RedBlackTree can be saved and loaded. Set it as the Codec of the tree.
+type NodeValueCodec interface {
+	EncodeValue(NodeValue) ([]byte, error)
+	DecodeValue([]byte) (NodeValue, error)
+}
+
+// MarshalBinary encodes the tree using its Codec
+func (t *RedBlackTree) MarshalBinary() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	_, err := t.WriteTo(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the contents of the tree with data created by
+// MarshalBinary, decoding values with the Codec of the tree
+func (t *RedBlackTree) UnmarshalBinary(data []byte) error {
+	_, err := t.ReadFrom(bytes.NewReader(data))
+
+	return err
+}
+
+// WriteTo writes a snapshot of the tree to w using its Codec. The snapshot
+// has a header with the number of distinct values, followed by each value
+// in order with its count and length.
+func (t *RedBlackTree) WriteTo(w io.Writer) (int64, error) {
+	var err error
+
+	if t.Codec == nil {
+		return 0, NoCodec
+	}
+
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+
+	// Write the header
+	cw.Write(snapshotMagic[:])
+	binary.Write(cw, binary.BigEndian, uint64(t.Size()))
+
+	// Write every node in order
+	t.eachNode(t.Root, func(n *Node) bool {
+		var b []byte
+
+		b, err = t.Codec.EncodeValue(n.Value)
+		if err != nil {
+			return false
+		}
+
+		binary.Write(cw, binary.BigEndian, uint64(n.ValueCount))
+		binary.Write(cw, binary.BigEndian, uint32(len(b)))
+		cw.Write(b)
+
+		return cw.err == nil
+	})
+
+	if err != nil {
+		return cw.n, err
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+
+	return cw.n, bw.Flush()
+}
+
+// ReadFrom replaces the contents of the tree with a snapshot read from r,
+// decoding values with the Codec of the tree. It reads exactly one
+// snapshot and nothing after it.
+func (t *RedBlackTree) ReadFrom(r io.Reader) (int64, error) {
+	var (
+		magic   [4]byte
+		size    uint64
+		count   uint64
+		length  uint32
+		entries []bulkEntry
+	)
+
+	if t.Codec == nil {
+		return 0, NoCodec
+	}
+
+	cr := &countReader{r: r}
+
+	// Read the header
+	_, err := io.ReadFull(cr, magic[:])
+	if err != nil {
+		return cr.n, err
+	}
+	if magic != snapshotMagic {
+		return cr.n, BadSnapshot
+	}
+
+	err = binary.Read(cr, binary.BigEndian, &size)
+	if err != nil {
+		return cr.n, err
+	}
+
+	// Read every value
+	for i := uint64(0); i < size; i++ {
+		err = binary.Read(cr, binary.BigEndian, &count)
+		if err != nil {
+			return cr.n, err
+		}
+
+		// Every value is there at least once, and its count has to fit
+		// in an int
+		if count < 1 || count > math.MaxInt {
+			return cr.n, BadSnapshot
+		}
+
+		err = binary.Read(cr, binary.BigEndian, &length)
+		if err != nil {
+			return cr.n, err
+		}
+
+		// The length comes from the snapshot, so don't trust it to
+		// size a buffer up front. Let the buffer grow as we read
+		// instead, so a bad length runs out of data before it can use
+		// up our memory.
+		var buf bytes.Buffer
+		_, err = io.CopyN(&buf, cr, int64(length))
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return cr.n, err
+		}
+
+		v, err := t.Codec.DecodeValue(buf.Bytes())
+		if err != nil {
+			return cr.n, err
+		}
+
+		entries = append(entries, bulkEntry{value: v, count: int(count)})
+	}
+
+	return cr.n, t.bulkLoad(entries)
+}
+
+// BulkLoad replaces the contents of the tree with values, which must be in
+// sorted order. Dupes are counted like they are by Put. This takes O(n)
+// time, instead of the O(n log n) time it takes to Put each value.
+func (t *RedBlackTree) BulkLoad(values []NodeValue) error {
+	var entries []bulkEntry
+
+	for _, v := range values {
+		last := len(entries) - 1
+
+		if last >= 0 && v.Equals(entries[last].value) {
+			entries[last].count++
+		} else {
+			entries = append(entries, bulkEntry{value: v, count: 1})
+		}
+	}
+
+	return t.bulkLoad(entries)
+}
+
+// A distinct value and its count, for bulk loading
+type bulkEntry struct {
+	value NodeValue
+	count int
+}
+
+// Replace the contents of the tree with entries, which must be in sorted
+// order with no dupes
+func (t *RedBlackTree) bulkLoad(entries []bulkEntry) error {
+	for i := 1; i < len(entries); i++ {
+		if !entries[i-1].value.Less(entries[i].value) {
+			return NotSorted
+		}
+	}
+
+	// The tallest 2-3 tree we can build has only 2-nodes. Its height is
+	// the black height of our red-black tree.
+	height := 0
+	for (1<<(height+1))-1 <= len(entries) {
+		height++
+	}
+
+	t.Root = t.build(entries, height)
+
+	return nil
+}
+
+// Recursively build a tree from entries where every path from the root to
+// a nil link crosses exactly height black nodes. We think of the tree as a
+// 2-3 tree, where a 3-node is two nodes joined by a red left link.
+func (t *RedBlackTree) build(entries []bulkEntry, height int) *Node {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	n := len(entries)
+
+	// Split the rest evenly between two children, with any remainder
+	// going to the left
+	leftN := (n - 1) - (n-1)/2
+
+	// Use a 2-node if its children can hold the rest of the entries,
+	// otherwise use a 3-node
+	if leftN <= maxEntries23(height-1) {
+		return t.newBulkNode(entries[leftN], black,
+			t.build(entries[:leftN], height-1),
+			t.build(entries[leftN+1:], height-1),
+		)
+	}
+
+	// Split the rest evenly between three children, with any remainder
+	// going to the left
+	third := (n - 2) / 3
+	leftN = third
+	midN := third
+	if (n-2)%3 > 0 {
+		leftN++
+	}
+	if (n-2)%3 > 1 {
+		midN++
+	}
+
+	left := t.newBulkNode(entries[leftN], red,
+		t.build(entries[:leftN], height-1),
+		t.build(entries[leftN+1:leftN+1+midN], height-1),
+	)
+
+	return t.newBulkNode(entries[leftN+1+midN], black,
+		left,
+		t.build(entries[leftN+2+midN:], height-1),
+	)
+}
+
+// Create a node for a bulk loaded tree
+func (t *RedBlackTree) newBulkNode(e bulkEntry, c color, left, right *Node) *Node {
+	n := &Node{
+		Left:       left,
+		Right:      right,
+		Color:      c,
+		ValueCount: e.count,
+		Value:      e.value,
+		version:    t.version,
+	}
+	t.update(n)
+
+	return n
+}
+
+// The most entries a 2-3 tree of this height can hold, which is when every
+// node is a 3-node. Capped so it doesn't overflow.
+func maxEntries23(height int) int {
+	max := 1
+	for i := 0; i < height; i++ {
+		if max > MaxIntVal/3 {
+			return MaxIntVal
+		}
+		max *= 3
+	}
+
+	return max - 1
+}
+
+// Call f on each node under n in order until it returns false. Returns
+// false if f asked us to stop.
+func (t *RedBlackTree) eachNode(n *Node, f func(*Node) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return t.eachNode(n.Left, f) && f(n) && t.eachNode(n.Right, f)
+}
+
+// A writer that counts bytes and remembers its first error
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+
+	return n, err
+}
+
+// A reader that counts bytes
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,146 @@
+package algo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Encode intNodes as 8 bytes
+type intNodeCodec struct{}
+
+func (intNodeCodec) EncodeValue(v NodeValue) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, uint64(v.(intNode))), nil
+}
+
+func (intNodeCodec) DecodeValue(b []byte) (NodeValue, error) {
+	return intNode(binary.BigEndian.Uint64(b)), nil
+}
+
+// Bulk load every size up to a few hundred and check the invariants
+func TestRedBlackBulkLoad(t *testing.T) {
+	for size := 0; size < 300; size++ {
+		var values []NodeValue
+		for i := 0; i < size; i++ {
+			values = append(values, intNode(i))
+		}
+
+		tree := RedBlackTree{}
+		err := tree.BulkLoad(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkTree(t, &tree)
+
+		if tree.Size() != size {
+			t.Fatalf("Expected size %v but got %v", size, tree.Size())
+		}
+
+		// The loaded tree should still work with Put and Delete
+		tree.Put(intNode(size))
+		tree.Delete(intNode(0))
+		checkTree(t, &tree)
+	}
+
+	tree := RedBlackTree{}
+	if tree.BulkLoad([]NodeValue{intNode(2), intNode(1)}) != NotSorted {
+		t.Fatal("Expected not sorted error")
+	}
+}
+
+// Save a tree and load it back
+func TestRedBlackSnapshot(t *testing.T) {
+	numVals := 5000
+
+	tree := RedBlackTree{Codec: intNodeCodec{}}
+	for i := 0; i < numVals; i++ {
+		tree.Put(intNode(rand.Intn(numVals)))
+	}
+
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := RedBlackTree{Codec: intNodeCodec{}}
+	err = loaded.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkTree(t, &loaded)
+
+	if loaded.Size() != tree.Size() {
+		t.Fatalf("Expected size %v but got %v", tree.Size(), loaded.Size())
+	}
+
+	// Every value and its count should match
+	c := loaded.NewCursor()
+	tree.eachNode(tree.Root, func(n *Node) bool {
+		if !c.Next() || c.Value() != n.Value {
+			t.Fatalf("Expected %v but got %v", n.Value, c.Value())
+		}
+
+		if loaded.find(loaded.Root, n.Value).ValueCount != n.ValueCount {
+			t.Fatalf("Expected count %v for %v", n.ValueCount, n.Value)
+		}
+
+		return true
+	})
+
+	// Streaming two snapshots back to back reads each one exactly
+	buf := &bytes.Buffer{}
+	written, err := tree.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != int64(len(data)) {
+		t.Fatalf("Expected to write %v bytes but wrote %v", len(data), written)
+	}
+	tree.WriteTo(buf)
+
+	for i := 0; i < 2; i++ {
+		read, err := loaded.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read != written {
+			t.Fatalf("Expected to read %v bytes but read %v", written, read)
+		}
+	}
+
+	if loaded.UnmarshalBinary([]byte("junk")) != BadSnapshot {
+		t.Fatal("Expected bad snapshot error")
+	}
+
+	// A huge length with only a few bytes after it should fail without
+	// allocating the whole length
+	huge := append([]byte{}, snapshotMagic[:]...)
+	huge = binary.BigEndian.AppendUint64(huge, 1)
+	huge = binary.BigEndian.AppendUint64(huge, 1)
+	huge = binary.BigEndian.AppendUint32(huge, 0xffffffff)
+	huge = append(huge, 1, 2, 3)
+	if loaded.UnmarshalBinary(huge) != io.ErrUnexpectedEOF {
+		t.Fatal("Expected unexpected EOF error")
+	}
+
+	// Counts that don't fit in an int are corrupt
+	for _, count := range []uint64{0, math.MaxInt + 1, math.MaxUint64} {
+		data := append([]byte{}, snapshotMagic[:]...)
+		data = binary.BigEndian.AppendUint64(data, 1)
+		data = binary.BigEndian.AppendUint64(data, count)
+		data = binary.BigEndian.AppendUint32(data, 8)
+		data = binary.BigEndian.AppendUint64(data, 1)
+		if loaded.UnmarshalBinary(data) != BadSnapshot {
+			t.Fatalf("Expected bad snapshot error for count %v", count)
+		}
+	}
+
+	if _, err := (&RedBlackTree{}).MarshalBinary(); err != NoCodec {
+		t.Fatal("Expected no codec error")
+	}
+}
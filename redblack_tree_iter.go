@@ -0,0 +1,212 @@
+package algo
+
+import (
+	"iter"
+)
+
+// All returns an iterator over every distinct value in the tree, from
+// smallest to largest
+func (t *RedBlackTree) All() iter.Seq[NodeValue] {
+	return func(yield func(NodeValue) bool) {
+		t.inOrder(t.Root, nil, nil, yield)
+	}
+}
+
+// Range returns an iterator over every distinct value in the tree between
+// lo and hi, inclusive, from smallest to largest
+func (t *RedBlackTree) Range(lo, hi NodeValue) iter.Seq[NodeValue] {
+	return func(yield func(NodeValue) bool) {
+		t.inOrder(t.Root, lo, hi, yield)
+	}
+}
+
+// Backward returns an iterator over every distinct value in the tree, from
+// largest to smallest
+func (t *RedBlackTree) Backward() iter.Seq[NodeValue] {
+	return func(yield func(NodeValue) bool) {
+		t.reverseOrder(t.Root, yield)
+	}
+}
+
+// Recursively yield values under n in order, skipping subtrees that are
+// entirely below lo or above hi. A nil lo or hi is unbounded. Returns false
+// if yield asked us to stop.
+func (t *RedBlackTree) inOrder(n *Node, lo, hi NodeValue, yield func(NodeValue) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	// Is n above lo and below hi?
+	aboveLo := lo == nil || lo.Less(n.Value)
+	belowHi := hi == nil || n.Value.Less(hi)
+
+	// Only values above lo can be on the left
+	if aboveLo && !t.inOrder(n.Left, lo, hi, yield) {
+		return false
+	}
+
+	if (aboveLo || lo.Equals(n.Value)) && (belowHi || hi.Equals(n.Value)) {
+		if !yield(n.Value) {
+			return false
+		}
+	}
+
+	// Only values below hi can be on the right
+	if belowHi && !t.inOrder(n.Right, lo, hi, yield) {
+		return false
+	}
+
+	return true
+}
+
+// Recursively yield values under n in reverse order. Returns false if
+// yield asked us to stop.
+func (t *RedBlackTree) reverseOrder(n *Node, yield func(NodeValue) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return t.reverseOrder(n.Right, yield) &&
+		yield(n.Value) &&
+		t.reverseOrder(n.Left, yield)
+}
+
+// RedBlackCursor moves back and forth over the values of a RedBlackTree in
+// order. A cursor is either positioned at a value or unpositioned. Changing
+// the tree invalidates any existing cursors.
+type RedBlackCursor struct {
+	tree *RedBlackTree
+
+	// The path from the root down to the current node. Empty when the
+	// cursor is unpositioned.
+	stack []*Node
+}
+
+// NewCursor creates an unpositioned cursor on the tree
+func (t *RedBlackTree) NewCursor() *RedBlackCursor {
+	return &RedBlackCursor{tree: t}
+}
+
+// Valid is true when the cursor is positioned at a value
+func (c *RedBlackCursor) Valid() bool {
+	return len(c.stack) > 0
+}
+
+// Value at the current position, or nil if unpositioned
+func (c *RedBlackCursor) Value() NodeValue {
+	if !c.Valid() {
+		return nil
+	}
+
+	return c.stack[len(c.stack)-1].Value
+}
+
+// Seek moves the cursor to the smallest value greater than or equal to v.
+// Returns false and leaves the cursor unpositioned if there is no such
+// value.
+func (c *RedBlackCursor) Seek(v NodeValue) bool {
+	// How much of the stack leads to our best candidate so far
+	candidate := 0
+
+	c.stack = c.stack[:0]
+
+	n := c.tree.Root
+	for n != nil {
+		c.stack = append(c.stack, n)
+
+		if v.Equals(n.Value) {
+			return true
+		}
+
+		if v.Less(n.Value) {
+			// This node is a candidate, but there may be a smaller one on
+			// the left
+			candidate = len(c.stack)
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+
+	c.stack = c.stack[:candidate]
+
+	return c.Valid()
+}
+
+// Next moves the cursor to the next larger value. An unpositioned cursor
+// moves to the smallest value. Returns false and leaves the cursor
+// unpositioned after the largest value.
+func (c *RedBlackCursor) Next() bool {
+	if !c.Valid() {
+		c.pushLeft(c.tree.Root)
+		return c.Valid()
+	}
+
+	n := c.stack[len(c.stack)-1]
+
+	// If we have a right subtree, the next value is its smallest
+	if n.Right != nil {
+		c.pushLeft(n.Right)
+		return true
+	}
+
+	// Otherwise go up until we come from a left child
+	for len(c.stack) > 1 {
+		child := c.stack[len(c.stack)-1]
+		c.stack = c.stack[:len(c.stack)-1]
+
+		if c.stack[len(c.stack)-1].Left == child {
+			return true
+		}
+	}
+
+	c.stack = c.stack[:0]
+	return false
+}
+
+// Prev moves the cursor to the next smaller value. An unpositioned cursor
+// moves to the largest value. Returns false and leaves the cursor
+// unpositioned before the smallest value.
+func (c *RedBlackCursor) Prev() bool {
+	if !c.Valid() {
+		c.pushRight(c.tree.Root)
+		return c.Valid()
+	}
+
+	n := c.stack[len(c.stack)-1]
+
+	// If we have a left subtree, the previous value is its largest
+	if n.Left != nil {
+		c.pushRight(n.Left)
+		return true
+	}
+
+	// Otherwise go up until we come from a right child
+	for len(c.stack) > 1 {
+		child := c.stack[len(c.stack)-1]
+		c.stack = c.stack[:len(c.stack)-1]
+
+		if c.stack[len(c.stack)-1].Right == child {
+			return true
+		}
+	}
+
+	c.stack = c.stack[:0]
+	return false
+}
+
+// Push n and every left child below it
+func (c *RedBlackCursor) pushLeft(n *Node) {
+	for n != nil {
+		c.stack = append(c.stack, n)
+		n = n.Left
+	}
+}
+
+// Push n and every right child below it
+func (c *RedBlackCursor) pushRight(n *Node) {
+	for n != nil {
+		c.stack = append(c.stack, n)
+		n = n.Right
+	}
+}
@@ -0,0 +1,123 @@
+package algo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Fill a tree with the even numbers from 0 to 2*(numVals-1)
+func evenTree(numVals int) *RedBlackTree {
+	tree := &RedBlackTree{}
+	for _, v := range rand.Perm(numVals) {
+		tree.Put(intNode(v * 2))
+	}
+
+	return tree
+}
+
+func TestRedBlackIterators(t *testing.T) {
+	numVals := 500
+	tree := evenTree(numVals)
+
+	i := 0
+	for v := range tree.All() {
+		if v != intNode(i*2) {
+			t.Fatalf("Expected %v but got %v", i*2, v)
+		}
+		i++
+	}
+	if i != numVals {
+		t.Fatalf("Expected %v values but got %v", numVals, i)
+	}
+
+	i = numVals - 1
+	for v := range tree.Backward() {
+		if v != intNode(i*2) {
+			t.Fatalf("Expected %v but got %v", i*2, v)
+		}
+		i--
+	}
+	if i != -1 {
+		t.Fatalf("Expected %v values but got %v", numVals, numVals-1-i)
+	}
+
+	// Ranges are inclusive, and the bounds don't need to be in the tree
+	var got []NodeValue
+	for v := range tree.Range(intNode(99), intNode(110)) {
+		got = append(got, v)
+	}
+	expected := []intNode{100, 102, 104, 106, 108, 110}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v but got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("Expected %v but got %v", expected, got)
+		}
+	}
+
+	// Stop early
+	i = 0
+	for range tree.All() {
+		i++
+		if i == 10 {
+			break
+		}
+	}
+}
+
+func TestRedBlackCursor(t *testing.T) {
+	numVals := 500
+	tree := evenTree(numVals)
+
+	c := tree.NewCursor()
+	if c.Valid() || c.Value() != nil {
+		t.Fatal("Expected unpositioned cursor")
+	}
+
+	// Walk forward over everything
+	i := 0
+	for c.Next() {
+		if c.Value() != intNode(i*2) {
+			t.Fatalf("Expected %v but got %v", i*2, c.Value())
+		}
+		i++
+	}
+	if i != numVals || c.Valid() {
+		t.Fatalf("Expected %v values but got %v", numVals, i)
+	}
+
+	// Walk backward over everything
+	i = numVals - 1
+	for c.Prev() {
+		if c.Value() != intNode(i*2) {
+			t.Fatalf("Expected %v but got %v", i*2, c.Value())
+		}
+		i--
+	}
+	if i != -1 || c.Valid() {
+		t.Fatalf("Expected %v values but got %v", numVals, numVals-1-i)
+	}
+
+	// Seek to a value that's there and one that isn't
+	if !c.Seek(intNode(100)) || c.Value() != intNode(100) {
+		t.Fatalf("Expected seek to 100 but got %v", c.Value())
+	}
+
+	if !c.Seek(intNode(101)) || c.Value() != intNode(102) {
+		t.Fatalf("Expected seek to 102 but got %v", c.Value())
+	}
+
+	// Then move both ways
+	if !c.Prev() || c.Value() != intNode(100) {
+		t.Fatalf("Expected 100 but got %v", c.Value())
+	}
+
+	if !c.Next() || !c.Next() || c.Value() != intNode(104) {
+		t.Fatalf("Expected 104 but got %v", c.Value())
+	}
+
+	if c.Seek(intNode(numVals * 2)) {
+		t.Fatalf("Expected nothing after the max but got %v", c.Value())
+	}
+}
@@ -0,0 +1,104 @@
+package algo
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// Compare order statistics against a sorted slice of the same values
+func TestRedBlackOrder(t *testing.T) {
+	numVals := 1000
+
+	tree := RedBlackTree{}
+
+	if _, err := tree.Min(); err != EmptyTree {
+		t.Fatal("Expected empty tree")
+	}
+
+	// Only use even values so we can look up odd values that aren't there
+	var sorted []int
+	for _, v := range rand.Perm(numVals) {
+		tree.Put(intNode(v * 2))
+		sorted = append(sorted, v*2)
+	}
+	sort.Ints(sorted)
+
+	// Dupes shouldn't change anything
+	tree.Put(intNode(sorted[10]))
+
+	if tree.Size() != numVals {
+		t.Fatalf("Expected size %v but got %v", numVals, tree.Size())
+	}
+
+	min, err := tree.Min()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min != intNode(sorted[0]) {
+		t.Fatalf("Expected min %v but got %v", sorted[0], min)
+	}
+
+	max, err := tree.Max()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max != intNode(sorted[numVals-1]) {
+		t.Fatalf("Expected max %v but got %v", sorted[numVals-1], max)
+	}
+
+	for k, v := range sorted {
+		selected, err := tree.Select(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if selected != intNode(v) {
+			t.Fatalf("Expected select(%v) to be %v but got %v", k, v, selected)
+		}
+
+		if tree.Rank(intNode(v)) != k {
+			t.Fatalf("Expected rank(%v) to be %v but got %v", v, k, tree.Rank(intNode(v)))
+		}
+
+		// The odd value after v isn't in the tree, but it has the same
+		// floor and the next ceiling
+		if tree.Rank(intNode(v+1)) != k+1 {
+			t.Fatalf("Expected rank(%v) to be %v but got %v", v+1, k+1, tree.Rank(intNode(v+1)))
+		}
+
+		floor, ok := tree.Floor(intNode(v + 1))
+		if !ok || floor != intNode(v) {
+			t.Fatalf("Expected floor(%v) to be %v but got %v", v+1, v, floor)
+		}
+
+		ceiling, ok := tree.Ceiling(intNode(v + 1))
+		if k < numVals-1 {
+			if !ok || ceiling != intNode(sorted[k+1]) {
+				t.Fatalf("Expected ceiling(%v) to be %v but got %v", v+1, sorted[k+1], ceiling)
+			}
+		} else if ok {
+			t.Fatalf("Expected no ceiling for %v but got %v", v+1, ceiling)
+		}
+	}
+
+	if _, ok := tree.Floor(intNode(-1)); ok {
+		t.Fatal("Expected no floor")
+	}
+
+	if _, err := tree.Select(numVals); err != RankOutOfRange {
+		t.Fatal("Expected rank out of range")
+	}
+
+	// Between 100 and 200 inclusive there are 51 even numbers
+	if tree.CountRange(intNode(100), intNode(200)) != 51 {
+		t.Fatalf("Expected 51 values in range but got %v", tree.CountRange(intNode(100), intNode(200)))
+	}
+
+	if tree.CountRange(intNode(99), intNode(201)) != 51 {
+		t.Fatalf("Expected 51 values in range but got %v", tree.CountRange(intNode(99), intNode(201)))
+	}
+
+	if tree.CountRange(intNode(200), intNode(100)) != 0 {
+		t.Fatal("Expected empty range")
+	}
+}
@@ -0,0 +1,114 @@
+package algo
+
+// SkewHeap is a self-adjusting version of LeftistHeap. Instead of tracking
+// ranks, it swaps the children of every node on the merge path, which gives
+// O(log n) amortized Meld, Insert and DelMax with less bookkeeping. Items
+// are ordered by the same PQLessFunc used by PriorityQueue.
+type SkewHeap[T any] struct {
+	root *heapNode[T]
+	n    int
+	less PQLessFunc[T]
+
+	// Reused by meld so it doesn't allocate every time
+	path []*heapNode[T]
+}
+
+// Create a new, empty skew heap ordered by less
+func NewSkewHeap[T any](less PQLessFunc[T]) *SkewHeap[T] {
+	return &SkewHeap[T]{less: less}
+}
+
+// Insert a new item into the heap
+func (h *SkewHeap[T]) Insert(item T) {
+	h.root = h.meld(h.root, &heapNode[T]{item: item})
+	h.n++
+}
+
+// GetMax returns the highest priority item without deleting it
+func (h *SkewHeap[T]) GetMax() (T, error) {
+	if h.IsEmpty() {
+		var zero T
+		return zero, PQEmpty
+	}
+
+	return h.root.item, nil
+}
+
+// Delete the highest priority item in our heap
+func (h *SkewHeap[T]) DelMax() (T, error) {
+	if h.IsEmpty() {
+		var zero T
+		return zero, PQEmpty
+	}
+
+	max := h.root.item
+
+	// The new root comes from melding the two subtrees of the old one
+	h.root = h.meld(h.root.left, h.root.right)
+	h.n--
+
+	return max, nil
+}
+
+// Meld moves every item from other into this heap in O(log n) amortized
+// time, leaving other empty. Both heaps must use the same ordering.
+func (h *SkewHeap[T]) Meld(other *SkewHeap[T]) {
+	if other == h {
+		return
+	}
+
+	h.root = h.meld(h.root, other.root)
+	h.n += other.n
+
+	other.root = nil
+	other.n = 0
+}
+
+// Is our heap empty?
+func (h *SkewHeap[T]) IsEmpty() bool {
+	return h.n < 1
+}
+
+// What is the current number of items on the heap?
+func (h *SkewHeap[T]) Size() int {
+	return h.n
+}
+
+// Meld the trees at a and b, returning the new root. A skew heap's right
+// spine is only short on average, so rather than recursing down it, we walk
+// down both right spines merging them, then go back up swapping the
+// children of every node on the way.
+func (h *SkewHeap[T]) meld(a, b *heapNode[T]) *heapNode[T] {
+	path := h.path[:0]
+
+	for a != nil && b != nil {
+		// Keep the higher priority node on the path, and meld its right
+		// child with the other tree next
+		if h.less(a.item, b.item) {
+			a, b = b, a
+		}
+
+		path = append(path, a)
+		a = a.right
+	}
+
+	// Whatever is left hangs off the bottom of the path
+	root := a
+	if root == nil {
+		root = b
+	}
+
+	// The melded tree below each node becomes its left child, and its
+	// old left child moves to the right
+	for i := len(path) - 1; i >= 0; i-- {
+		node := path[i]
+		node.left, node.right = root, node.left
+		root = node
+	}
+
+	// Don't hold on to nodes that might be deleted later
+	clear(path)
+	h.path = path[:0]
+
+	return root
+}
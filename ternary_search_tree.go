@@ -0,0 +1,289 @@
+package algo
+
+import (
+	"unsafe"
+)
+
+// TernarySearchTree is a node in a ternary search tree. It holds words like
+// a Trie, but instead of a list of siblings, the nodes for the next letter
+// of a word form a binary search tree under Mid, using Left and Right. Like
+// any unbalanced binary search tree, it works best when words aren't added
+// in sorted order.
+type TernarySearchTree struct {
+	// The letter this node represents
+	Letter rune
+
+	// Value is a bitmask, the same as Trie.Value. A zero value must
+	// represent a non-word.
+	Value int64
+
+	// Nodes for letters before and after this one, at the same spot in
+	// a word
+	Left, Right *TernarySearchTree
+
+	// The tree of nodes for the next letter
+	Mid *TernarySearchTree
+}
+
+// NewTernarySearchTree creates a new tree root with 0 as the value.
+func NewTernarySearchTree() *TernarySearchTree {
+	return &TernarySearchTree{}
+}
+
+// findChild finds the node for this rune in the tree under t, or returns nil
+func (t *TernarySearchTree) findChild(letter rune) *TernarySearchTree {
+	node := t.Mid
+
+	for node != nil {
+		switch {
+		case letter < node.Letter:
+			node = node.Left
+		case letter > node.Letter:
+			node = node.Right
+		default:
+			return node
+		}
+	}
+
+	return nil
+}
+
+// ensureChild ensures that a node for this letter exists in the tree under
+// t. Returns the node itself, whether this node was newly created, and how
+// deep in the tree it is.
+func (t *TernarySearchTree) ensureChild(letter rune) (*TernarySearchTree, bool, int) {
+	var depth int
+
+	// Follow links down until we find the node or the nil link where it
+	// belongs
+	link := &t.Mid
+	for *link != nil {
+		node := *link
+
+		switch {
+		case letter < node.Letter:
+			link = &node.Left
+		case letter > node.Letter:
+			link = &node.Right
+		default:
+			return node, false, depth
+		}
+
+		depth++
+	}
+
+	*link = &TernarySearchTree{Letter: letter}
+
+	return *link, true, depth
+}
+
+// Add a word to the tree. Returns how many new nodes were created, and the
+// maximum depth of a node among the others for the same letter of the word.
+func (t *TernarySearchTree) Add(word string, value int64) (int, int) {
+	var newNodes, maxDepth int
+
+	node := t
+
+	for _, letter := range word {
+		child, newNode, depth := node.ensureChild(letter)
+
+		if newNode {
+			newNodes++
+		}
+
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+
+		node = child
+	}
+
+	// Set new value of this node by running OR on existing value
+	node.Value = node.Value | value
+
+	return newNodes, maxDepth
+}
+
+// Exists returns a boolean indicating whether this word exists or not in our
+// tree. It also returns the node when found.
+func (t *TernarySearchTree) Exists(word string) (bool, *TernarySearchTree) {
+	node := t
+
+	for _, letter := range word {
+		node = node.findChild(letter)
+		if node == nil {
+			return false, nil
+		}
+	}
+
+	return node.Value > 0, node
+}
+
+// unlinkChild removes child from the tree under t, keeping the rest of the
+// tree in order. This is the usual binary search tree delete: if child has
+// nodes on both sides, the next letter after it takes its place.
+func (t *TernarySearchTree) unlinkChild(child *TernarySearchTree) {
+	// Find the link that points to child
+	link := &t.Mid
+	for *link != child {
+		if child.Letter < (*link).Letter {
+			link = &(*link).Left
+		} else {
+			link = &(*link).Right
+		}
+	}
+
+	switch {
+	case child.Left == nil:
+		*link = child.Right
+	case child.Right == nil:
+		*link = child.Left
+	default:
+		// The next letter is the leftmost node on the right
+		next := &child.Right
+		for (*next).Left != nil {
+			next = &(*next).Left
+		}
+
+		successor := *next
+		*next = successor.Right
+
+		successor.Left = child.Left
+		successor.Right = child.Right
+		*link = successor
+	}
+
+	child.Left = nil
+	child.Right = nil
+}
+
+// ClearBits turns off bits in the value of a word that is already in the
+// tree. If no bits are left, the word is removed, along with any nodes that
+// no longer lead to a word. Returns whether the word existed and how many
+// nodes were removed.
+func (t *TernarySearchTree) ClearBits(word string, bits int64) (bool, int) {
+	var removed int
+
+	// Remember every node on the way down so we can prune on the way back
+	path := []*TernarySearchTree{t}
+	for _, letter := range word {
+		node := path[len(path)-1].findChild(letter)
+		if node == nil {
+			return false, 0
+		}
+
+		path = append(path, node)
+	}
+
+	node := path[len(path)-1]
+	if node.Value == 0 {
+		return false, 0
+	}
+
+	node.Value = node.Value &^ bits
+
+	// Unlink nodes that aren't words and have nothing after them,
+	// starting at the end of the word. Never remove the root.
+	for i := len(path) - 1; i > 0; i-- {
+		node = path[i]
+		if node.Value != 0 || node.Mid != nil {
+			break
+		}
+
+		path[i-1].unlinkChild(node)
+		removed++
+	}
+
+	return true, removed
+}
+
+type TernaryCompletion struct {
+	Word string
+	Node *TernarySearchTree
+}
+
+// FindCompletions does a breadth-first search below this node, and finds up
+// to max completed words under it. The words for each letter come out in
+// order.
+func (t *TernarySearchTree) FindCompletions(word string, maxWords int) []TernaryCompletion {
+	var completions []TernaryCompletion
+
+	if maxWords < 1 {
+		return completions
+	}
+
+	type queued struct {
+		word string
+		node *TernarySearchTree
+	}
+
+	queue := []queued{{word, t}}
+	for len(queue) > 0 {
+		q := queue[0]
+		queue = queue[1:]
+
+		// Go through the tree of next letters in order
+		var stack []*TernarySearchTree
+		child := q.node.Mid
+
+		for child != nil || len(stack) > 0 {
+			for child != nil {
+				stack = append(stack, child)
+				child = child.Left
+			}
+
+			child = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			childWord := q.word + string(child.Letter)
+
+			// If it's a word, add it to our words
+			if child.Value > 0 {
+				completions = append(completions, TernaryCompletion{
+					Word: childWord,
+					Node: child,
+				})
+
+				// If we have enough words, then stop
+				if len(completions) >= maxWords {
+					return completions
+				}
+			}
+
+			queue = append(queue, queued{childWord, child})
+			child = child.Right
+		}
+	}
+
+	return completions
+}
+
+// Stats counts the nodes and words under this node, including the node
+// itself, and estimates how much memory they use
+func (t *TernarySearchTree) Stats() TrieStats {
+	var stats TrieStats
+
+	t.stats(&stats, false)
+
+	return stats
+}
+
+// Count this node and everything under it, and its siblings too if asked
+func (t *TernarySearchTree) stats(stats *TrieStats, siblings bool) {
+	stats.Nodes++
+	stats.Bytes += int(unsafe.Sizeof(*t))
+	if t.Value > 0 {
+		stats.Words++
+	}
+
+	if t.Mid != nil {
+		t.Mid.stats(stats, true)
+	}
+
+	if siblings && t.Left != nil {
+		t.Left.stats(stats, true)
+	}
+	if siblings && t.Right != nil {
+		t.Right.stats(stats, true)
+	}
+}
@@ -0,0 +1,143 @@
+package algo
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestTernarySearchTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	titles := randomTitles(3000, r)
+
+	trie := NewTrie()
+	tst := NewTernarySearchTree()
+	for i, title := range titles {
+		trie.Add(title, int64(i%3+1))
+		tst.Add(title, int64(i%3+1))
+	}
+
+	if trie.Stats().Words != tst.Stats().Words || trie.Stats().Nodes != tst.Stats().Nodes {
+		t.Fatalf("Expected %+v but got %+v", trie.Stats(), tst.Stats())
+	}
+
+	// Every prefix should give the same answers. Both search breadth
+	// first with letters in order, so even the order should match.
+	for _, title := range titles {
+		runes := []rune(title)
+
+		for i := 0; i <= len(runes); i++ {
+			prefix := string(runes[:i])
+
+			trieExists, trieNode := trie.Exists(prefix)
+			tstExists, tstNode := tst.Exists(prefix)
+
+			if trieExists != tstExists || trieNode.Value != tstNode.Value {
+				t.Fatalf("%q exists: %v but tst: %v", prefix, trieExists, tstExists)
+			}
+
+			var expected, actual []string
+			for _, c := range trieNode.FindCompletions(prefix, 10) {
+				expected = append(expected, fmt.Sprint(c.Word, c.Node.Value))
+			}
+			for _, c := range tstNode.FindCompletions(prefix, 10) {
+				actual = append(actual, fmt.Sprint(c.Word, c.Node.Value))
+			}
+
+			if fmt.Sprint(expected) != fmt.Sprint(actual) {
+				t.Fatalf("%q completions: %v but tst: %v", prefix, expected, actual)
+			}
+		}
+	}
+
+	// Clearing bits should prune the same nodes as Trie
+	for _, title := range titles {
+		bits := int64(r.Intn(4))
+
+		trieExists, trieRemoved := trie.ClearBits(title, bits)
+		tstExists, tstRemoved := tst.ClearBits(title, bits)
+		if trieExists != tstExists || trieRemoved != tstRemoved {
+			t.Fatalf("Clearing %q: %v, %v but tst: %v, %v", title, trieExists, trieRemoved, tstExists, tstRemoved)
+		}
+	}
+	if trie.Stats().Words != tst.Stats().Words || trie.Stats().Nodes != tst.Stats().Nodes {
+		t.Fatalf("Expected %+v but got %+v", trie.Stats(), tst.Stats())
+	}
+
+	// Whatever is left should still be in order
+	var expected, actual []string
+	for _, c := range trie.FindCompletions("", len(titles)) {
+		expected = append(expected, fmt.Sprint(c.Word, c.Node.Value))
+	}
+	for _, c := range tst.FindCompletions("", len(titles)) {
+		actual = append(actual, fmt.Sprint(c.Word, c.Node.Value))
+	}
+	if fmt.Sprint(expected) != fmt.Sprint(actual) {
+		t.Fatalf("Completions after clearing don't match")
+	}
+
+	if exists, node := tst.Exists("qqqq"); exists || node != nil {
+		t.Fatal("Found word not in tree")
+	}
+
+	// Clearing bits
+	tst = NewTernarySearchTree()
+	for _, word := range []string{"cat", "cats", "car", "ca"} {
+		tst.Add(word, 1)
+	}
+
+	if exists, _ := tst.ClearBits("cat", 0xff); !exists {
+		t.Fatal("Can't clear bits of cat")
+	}
+	if exists, _ := tst.Exists("cat"); exists {
+		t.Fatal("cat still exists")
+	}
+	if exists, _ := tst.ClearBits("cat", 1); exists {
+		t.Fatal("Cleared bits of missing word")
+	}
+
+	var words []string
+	_, node := tst.Exists("ca")
+	for _, c := range node.FindCompletions("ca", 10) {
+		words = append(words, c.Word)
+	}
+	if !sort.StringsAreSorted(words) || fmt.Sprint(words) != "[car cats]" {
+		t.Fatalf("Unexpected completions %v", words)
+	}
+}
+
+// Compare with Trie on titles like the ones wikisearch loads
+func BenchmarkTernarySearchTree(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	titles := randomTitles(100000, r)
+
+	b.Run("add", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tst := NewTernarySearchTree()
+			for _, title := range titles {
+				tst.Add(title, 1)
+			}
+		}
+	})
+
+	tst := NewTernarySearchTree()
+	for _, title := range titles {
+		tst.Add(title, 1)
+	}
+	b.Logf("%+v", tst.Stats())
+
+	b.Run("exists", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tst.Exists(titles[i%len(titles)])
+		}
+	})
+
+	b.Run("completions", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			prefix := string([]rune(titles[i%len(titles)])[:1])
+			_, node := tst.Exists(prefix)
+			node.FindCompletions(prefix, 25)
+		}
+	})
+}
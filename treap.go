@@ -0,0 +1,286 @@
+package algo
+
+import (
+	"iter"
+	"math/rand"
+)
+
+// Treap is a binary search tree on values that is also a heap on random
+// priorities. The random priorities make the shape of the tree the same as
+// if values had been put in random order, so it is balanced with high
+// probability and needs very little bookkeeping.
+type Treap struct {
+	root *treapNode
+}
+
+// A node on a Treap
+type treapNode struct {
+	left  *treapNode
+	right *treapNode
+
+	// Random priority, parents always have a higher priority than their
+	// children
+	priority int64
+
+	// Number of nodes under this one
+	nodeCount int
+
+	// If we get a dupe value, increment this count
+	valueCount int
+
+	value NodeValue
+}
+
+// Create a new, empty treap
+func NewTreap() *Treap {
+	return &Treap{}
+}
+
+// Put a new value into the treap
+func (t *Treap) Put(v NodeValue) {
+	t.root = t.put(t.root, v)
+}
+
+// Recursively put v into the treap, returning potential replacement for n
+func (t *Treap) put(n *treapNode, v NodeValue) *treapNode {
+	if n == nil {
+		return &treapNode{
+			value:      v,
+			priority:   rand.Int63(),
+			nodeCount:  1,
+			valueCount: 1,
+		}
+	}
+
+	if v.Equals(n.value) {
+		n.valueCount++
+		return n
+	}
+
+	// Put v as a leaf, then rotate it up while its priority is higher
+	// than ours
+	if v.Less(n.value) {
+		n.left = t.put(n.left, v)
+		if n.left.priority > n.priority {
+			n = t.rotateRight(n)
+		}
+	} else {
+		n.right = t.put(n.right, v)
+		if n.right.priority > n.priority {
+			n = t.rotateLeft(n)
+		}
+	}
+
+	n.update()
+
+	return n
+}
+
+// Can we find value v in the treap?
+func (t *Treap) Find(v NodeValue) bool {
+	return t.find(v) != nil
+}
+
+// Find the node holding v or nil
+func (t *Treap) find(v NodeValue) *treapNode {
+	n := t.root
+
+	for n != nil {
+		if v.Equals(n.value) {
+			return n
+		}
+
+		if v.Less(n.value) {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+
+	return nil
+}
+
+// Delete one copy of value v from the treap. Returns false if v is not in
+// the treap.
+func (t *Treap) Delete(v NodeValue) bool {
+	n := t.find(v)
+	if n == nil {
+		return false
+	}
+
+	// If we have dupes, just decrement the count
+	if n.valueCount > 1 {
+		n.valueCount--
+		return true
+	}
+
+	t.root = t.delete(t.root, v)
+
+	return true
+}
+
+// Recursively delete the node holding v under n, returning potential
+// replacement for n. v must be in the treap.
+func (t *Treap) delete(n *treapNode, v NodeValue) *treapNode {
+	if v.Equals(n.value) {
+		// Our children take our place
+		return t.join(n.left, n.right)
+	}
+
+	if v.Less(n.value) {
+		n.left = t.delete(n.left, v)
+	} else {
+		n.right = t.delete(n.right, v)
+	}
+
+	n.update()
+
+	return n
+}
+
+// Join two treaps where every value in left is less than every value in
+// right, returning the new root
+func (t *Treap) join(left, right *treapNode) *treapNode {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	// The root with the higher priority stays on top
+	if left.priority > right.priority {
+		left.right = t.join(left.right, right)
+		left.update()
+		return left
+	}
+
+	right.left = t.join(left, right.left)
+	right.update()
+	return right
+}
+
+// How many distinct values are in the treap?
+func (t *Treap) Size() int {
+	return t.root.nodeCountZeroNil()
+}
+
+// What is the height of the treap? An empty treap is -1, just like
+// RedBlackTree.
+func (t *Treap) Height() int {
+	return t.height(t.root)
+}
+
+// Private helper function for treap height
+func (t *Treap) height(n *treapNode) int {
+	if n == nil {
+		return -1
+	}
+
+	return 1 + MaxInt(t.height(n.left), t.height(n.right))
+}
+
+// Smallest value in the treap
+func (t *Treap) Min() (NodeValue, error) {
+	if t.root == nil {
+		return nil, EmptyTree
+	}
+
+	n := t.root
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n.value, nil
+}
+
+// Largest value in the treap
+func (t *Treap) Max() (NodeValue, error) {
+	if t.root == nil {
+		return nil, EmptyTree
+	}
+
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n.value, nil
+}
+
+// All returns an iterator over every distinct value in the treap, from
+// smallest to largest
+func (t *Treap) All() iter.Seq[NodeValue] {
+	return t.Range(nil, nil)
+}
+
+// Range returns an iterator over every distinct value in the treap between
+// lo and hi, inclusive, from smallest to largest
+func (t *Treap) Range(lo, hi NodeValue) iter.Seq[NodeValue] {
+	return func(yield func(NodeValue) bool) {
+		t.inOrder(t.root, lo, hi, yield)
+	}
+}
+
+// Recursively yield values under n in order, skipping subtrees that are
+// out of range. Returns false if yield asked us to stop.
+func (t *Treap) inOrder(n *treapNode, lo, hi NodeValue, yield func(NodeValue) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	aboveLo := atLeast(n.value, lo)
+	belowHi := atMost(n.value, hi)
+
+	if aboveLo && !t.inOrder(n.left, lo, hi, yield) {
+		return false
+	}
+
+	if aboveLo && belowHi && !yield(n.value) {
+		return false
+	}
+
+	if belowHi && !t.inOrder(n.right, lo, hi, yield) {
+		return false
+	}
+
+	return true
+}
+
+// Rotate left at op (old parent), returning the new parent
+func (t *Treap) rotateLeft(op *treapNode) *treapNode {
+	np := op.right
+	op.right = np.left
+	np.left = op
+
+	op.update()
+	np.update()
+
+	return np
+}
+
+// Rotate right at op (old parent), returning the new parent
+func (t *Treap) rotateRight(op *treapNode) *treapNode {
+	np := op.left
+	op.left = np.right
+	np.right = op
+
+	op.update()
+	np.update()
+
+	return np
+}
+
+// Recalculate node count from our children
+func (n *treapNode) update() {
+	n.nodeCount = n.left.nodeCountZeroNil() + n.right.nodeCountZeroNil() + 1
+}
+
+// Private helper function to get the node count of a possibly nil node
+func (n *treapNode) nodeCountZeroNil() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.nodeCount
+}
@@ -1,5 +1,13 @@
 package algo
 
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"unicode/utf8"
+	"unsafe"
+)
+
 // Trie is a node in our Trie structure
 type Trie struct {
 	// The letter this node represents
@@ -10,11 +18,37 @@ type Trie struct {
 	// a non-zero can be interpreted by the client however it wants.
 	Value int64
 
-	// A pointer to the next sibling of this node
+	// A pointer to the next sibling of this node. Siblings are kept in
+	// order by letter.
 	Sibling *Trie
 
 	// A pointer to the first child of this node
 	Child *Trie
+
+	// Weight ranks this word against others in FindTopCompletions. Set
+	// it with SetWeight. Weights should not be negative.
+	Weight int64
+
+	// The highest weight of any word at or below this node
+	maxWeight int64
+
+	// Once a node has many children, this lets us binary search them
+	index *trieIndex
+}
+
+// How many children a node needs before we index them
+var trieIndexFanout = 16
+
+// trieIndex holds the children of a trie node in a sorted slice
+type trieIndex struct {
+	children []*Trie
+}
+
+// search finds where letter is or would be in the children
+func (ti *trieIndex) search(letter rune) (int, bool) {
+	return slices.BinarySearchFunc(ti.children, letter, func(child *Trie, letter rune) int {
+		return cmp.Compare(child.Letter, letter)
+	})
 }
 
 // NewTrie creates a new trie root with 0 as the value.
@@ -27,13 +61,50 @@ func newTrieNode(letter rune) *Trie {
 	return &Trie{Letter: letter}
 }
 
+// Trie nodes work with the searches in trie_node.go
+
+func (t *Trie) nodeLetter() rune     { return t.Letter }
+func (t *Trie) nodeValue() int64     { return t.Value }
+func (t *Trie) nodeWeight() int64    { return t.Weight }
+func (t *Trie) nodeMaxWeight() int64 { return t.maxWeight }
+
+func (t *Trie) nodeBytes() int {
+	bytes := int(unsafe.Sizeof(*t))
+	if t.index != nil {
+		bytes += int(unsafe.Sizeof(*t.index)) + cap(t.index.children)*int(unsafe.Sizeof(t))
+	}
+
+	return bytes
+}
+
+func (t *Trie) nodeChildren() iter.Seq[*Trie] {
+	return func(yield func(*Trie) bool) {
+		for child := t.Child; child != nil; child = child.Sibling {
+			if !yield(child) {
+				return
+			}
+		}
+	}
+}
+
 // findChild finds a trie node for this rune at one level below t or returns
 // nil
 func (t *Trie) findChild(letter rune) *Trie {
+	// Binary search high fanout nodes
+	if t.index != nil {
+		i, found := t.index.search(letter)
+		if found {
+			return t.index.children[i]
+		}
+
+		return nil
+	}
+
 	child := t.Child
 
-	// Check all siblings for this letter
-	for child != nil {
+	// Check siblings for this letter. They're sorted, so we can stop as
+	// soon as we pass where it would be.
+	for child != nil && child.Letter <= letter {
 		if child.Letter == letter {
 			return child
 		}
@@ -57,30 +128,64 @@ func (t *Trie) ensureChild(letter rune) (*Trie, bool, int) {
 	// Can we find the child already?
 	child := t.findChild(letter)
 
-	// If not, create a node and append it to the children list
+	// If not, create a node and put it in order in the children list
 	if child == nil {
 		child = newTrieNode(letter)
 		newNode = true
+		siblings = t.insertChild(child)
+	}
+
+	return child, newNode, siblings
+}
+
+// insertChild links child into the children list of t, keeping it sorted by
+// letter. Returns how many siblings the child has.
+func (t *Trie) insertChild(child *Trie) int {
+	var (
+		prev     *Trie
+		siblings int
+	)
 
-		if t.Child == nil {
-			// If it's the first child, just set it
-			t.Child = child
+	if t.index != nil {
+		// Find the node before the child with a binary search
+		i, _ := t.index.search(child.Letter)
+		if i > 0 {
+			prev = t.index.children[i-1]
+		}
 
-		} else {
-			// Otherwise, find the tail and set its
-			// sibling to the new child
-			tail := t.Child
+		t.index.children = slices.Insert(t.index.children, i, child)
+		siblings = len(t.index.children) - 1
 
-			for tail.Sibling != nil {
-				tail = tail.Sibling
-				siblings++
+	} else {
+		// Find the node before the child, counting siblings as we
+		// go through the whole list
+		for node := t.Child; node != nil; node = node.Sibling {
+			if node.Letter < child.Letter {
+				prev = node
 			}
+			siblings++
+		}
+	}
+
+	if prev == nil {
+		// It's the new first child
+		child.Sibling = t.Child
+		t.Child = child
 
-			tail.Sibling = child
+	} else {
+		child.Sibling = prev.Sibling
+		prev.Sibling = child
+	}
+
+	// Switch to binary search once there are enough children
+	if t.index == nil && siblings >= trieIndexFanout {
+		t.index = &trieIndex{}
+		for node := t.Child; node != nil; node = node.Sibling {
+			t.index.children = append(t.index.children, node)
 		}
 	}
 
-	return child, newNode, siblings
+	return siblings
 }
 
 // Add a word to the trie. Returns how many new nodes were created, and the
@@ -141,63 +246,361 @@ func (t *Trie) Exists(word string) (bool, *Trie) {
 	return node.Value > 0, node
 }
 
+// unlinkChild removes child from the children list of t
+func (t *Trie) unlinkChild(child *Trie) {
+	var prev *Trie
+
+	if t.index != nil {
+		i, found := t.index.search(child.Letter)
+		if !found {
+			return
+		}
+		if i > 0 {
+			prev = t.index.children[i-1]
+		}
+
+		t.index.children = slices.Delete(t.index.children, i, i+1)
+
+	} else {
+		for node := t.Child; node != nil && node != child; node = node.Sibling {
+			prev = node
+		}
+	}
+
+	if prev == nil {
+		t.Child = child.Sibling
+	} else {
+		prev.Sibling = child.Sibling
+	}
+}
+
+// Remove a word from the trie, pruning any nodes that no longer lead to a
+// word. Returns whether the word existed and how many nodes were removed.
+func (t *Trie) Remove(word string) (bool, int) {
+	return t.SetValue(word, 0)
+}
+
+// SetValue replaces the value of a word that is already in the trie. Setting
+// it to zero removes the word. Returns whether the word existed and how many
+// nodes were removed.
+func (t *Trie) SetValue(word string, value int64) (bool, int) {
+	var removed int
+
+	// Remember every node on the way down so we can prune on the way back
+	path := t.path(word)
+	if path == nil {
+		return false, 0
+	}
+
+	node := path[len(path)-1]
+	if node.Value == 0 {
+		return false, 0
+	}
+
+	oldWeight := node.Weight
+
+	node.Value = value
+	if value == 0 {
+		node.Weight = 0
+	}
+	newWeight := node.Weight
+
+	// Unlink nodes that aren't words and have no children, starting at
+	// the end of the word. Never remove the root.
+	for i := len(path) - 1; i > 0; i-- {
+		node = path[i]
+		if node.Value != 0 || node.Child != nil {
+			break
+		}
+
+		path[i-1].unlinkChild(node)
+		removed++
+	}
+
+	updateMaxWeights(path[:len(path)-removed], oldWeight, newWeight)
+
+	return true, removed
+}
+
+// SetWeight sets the weight of a word that is already in the trie. Returns
+// whether the word existed.
+func (t *Trie) SetWeight(word string, weight int64) bool {
+	path := t.path(word)
+	if path == nil || path[len(path)-1].Value == 0 {
+		return false
+	}
+
+	node := path[len(path)-1]
+	oldWeight := node.Weight
+
+	node.Weight = weight
+	updateMaxWeights(path, oldWeight, weight)
+
+	return true
+}
+
+// path returns every node from t to the end of word, or nil if word isn't
+// in the trie
+func (t *Trie) path(word string) []*Trie {
+	path := []*Trie{t}
+	node := t
+
+	for _, letter := range word {
+		node = node.findChild(letter)
+		if node == nil {
+			return nil
+		}
+
+		path = append(path, node)
+	}
+
+	return path
+}
+
+// updateMaxWeights fixes the highest weight under each node in path, from
+// the bottom up, after the weight of a word at the bottom went from one
+// value to another. A higher weight just goes up the path. A lower one only
+// matters where it was the highest, so that's the only time we look at the
+// children of a node. We stop as soon as a node's highest weight stays the
+// same.
+func updateMaxWeights(path []*Trie, from, to int64) {
+	for i := len(path) - 1; i >= 0; i-- {
+		node := path[i]
+		maxWeight := node.maxWeight
+
+		switch {
+		case to >= maxWeight:
+			node.maxWeight = to
+		case from < maxWeight:
+			// Something else here was already higher
+			return
+		default:
+			node.maxWeight = 0
+			if node.Value > 0 {
+				node.maxWeight = node.Weight
+			}
+
+			for child := node.Child; child != nil; child = child.Sibling {
+				node.maxWeight = max(node.maxWeight, child.maxWeight)
+			}
+		}
+
+		if node.maxWeight == maxWeight {
+			return
+		}
+
+		from, to = maxWeight, node.maxWeight
+	}
+}
+
+// ClearBits turns off bits in the value of a word that is already in the
+// trie. If no bits are left, the word is removed. Returns whether the word
+// existed and how many nodes were removed.
+func (t *Trie) ClearBits(word string, bits int64) (bool, int) {
+	_, node := t.Exists(word)
+	if node == nil || node.Value == 0 {
+		return false, 0
+	}
+
+	return t.SetValue(word, node.Value&^bits)
+}
+
+// FindTopCompletions finds up to maxWords completed words under this trie
+// node with the highest weights, best first. Each node knows the best weight
+// below it, so we only visit subtrees that could still have a top word.
+func (t *Trie) FindTopCompletions(word string, maxWords int) []Completion {
+	return findTopCompletions(t, word, maxWords, newCompletion)
+}
+
+// Completion is a word found below a trie node and the node it ends at
 type Completion struct {
 	Word string
 	Node *Trie
 }
 
+func newCompletion(word string, node *Trie) Completion {
+	return Completion{Word: word, Node: node}
+}
+
 // FindCompletions does a breadth-first search below this trie node, and
 // finds up to max completed words under it.
 func (t *Trie) FindCompletions(word string, maxWords int) []Completion {
+	return findCompletions(t, word, maxWords, newCompletion)
+}
+
+// TrieStats describes the size of a trie
+type TrieStats struct {
+	// How many nodes there are, including the root
+	Nodes int
+
+	// How many nodes have a non-zero value
+	Words int
+
+	// Roughly how many bytes the nodes take up
+	Bytes int
+}
+
+// Stats counts the nodes and words under this node, including the node
+// itself, and estimates how much memory they use
+func (t *Trie) Stats() TrieStats {
+	return trieStats(t)
+}
+
+// A word found by FuzzySearch and how many edits away it is
+type FuzzyMatch struct {
+	Word  string
+	Node  *Trie
+	Edits int
+}
+
+// FuzzySearch finds up to limit words in the trie within maxEdits
+// Levenshtein distance of word, where an edit is inserting, deleting or
+// changing one letter. Matches come back closest first, then by weight.
+func (t *Trie) FuzzySearch(word string, maxEdits, limit int) []FuzzyMatch {
+	return fuzzySearch(t, word, maxEdits, limit, false, newFuzzyMatch)
+}
+
+// DamerauFuzzySearch is like FuzzySearch but also counts swapping two
+// letters next to each other as one edit.
+func (t *Trie) DamerauFuzzySearch(word string, maxEdits, limit int) []FuzzyMatch {
+	return fuzzySearch(t, word, maxEdits, limit, true, newFuzzyMatch)
+}
+
+func newFuzzyMatch(word string, node *Trie, edits int) FuzzyMatch {
+	return FuzzyMatch{Word: word, Node: node, Edits: edits}
+}
+
+// Walk calls f on every word at or below this node in lexicographic order,
+// until f returns false. word should be the path to this node. Returns false
+// if f asked us to stop.
+func (t *Trie) Walk(word string, f func(word string, node *Trie) bool) bool {
+	if t.Value > 0 && !f(word, t) {
+		return false
+	}
+
+	for child := t.Child; child != nil; child = child.Sibling {
+		if !child.Walk(word+string(child.Letter), f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// All returns an iterator over every word in the trie and its node, in
+// lexicographic order
+func (t *Trie) All() iter.Seq2[string, *Trie] {
+	return func(yield func(string, *Trie) bool) {
+		t.Walk("", yield)
+	}
+}
+
+// KeysWithPrefix returns an iterator over every word in the trie that
+// starts with prefix, including prefix itself, in lexicographic order
+func (t *Trie) KeysWithPrefix(prefix string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		_, node := t.Exists(prefix)
+		if node == nil {
+			return
+		}
+
+		node.Walk(prefix, func(word string, _ *Trie) bool {
+			return yield(word)
+		})
+	}
+}
+
+// LongestPrefixOf finds the longest word in the trie that s starts with.
+// Returns the word and its node, or nil if there isn't one.
+func (t *Trie) LongestPrefixOf(s string) (string, *Trie) {
 	var (
-		child       *Trie
-		childWord   string
-		completions []Completion
-		tmp         interface{}
+		longest string
+		found   *Trie
 	)
 
-	wordQ := NewQueue()
-	trieQ := NewQueue()
-	wordQ.Enqueue(word)
-	trieQ.Enqueue(t)
-
-	// While we still have nodes in our queue
-	for !wordQ.IsEmpty() && !trieQ.IsEmpty() {
-
-		// Get the word and trie node off the queue
-		tmp, _ = wordQ.Dequeue()
-		word = tmp.(string)
-		tmp, _ = trieQ.Dequeue()
-		child = tmp.(*Trie).Child
-
-		// Check for children that complete a word
-		for child != nil {
-			childWord = word + string(child.Letter)
-
-			// If it's a word, add it to our words
-			if child.Value > 0 {
-				completion := Completion{
-					Word: childWord,
-					Node: child,
-				}
-				completions = append(completions, completion)
-			}
+	if t.Value > 0 {
+		found = t
+	}
+
+	node := t
+	for i, letter := range s {
+		node = node.findChild(letter)
+		if node == nil {
+			break
+		}
+
+		if node.Value > 0 {
+			longest = s[:i+utf8.RuneLen(letter)]
+			found = node
+		}
+	}
+
+	return longest, found
+}
+
+// KeysThatMatch returns an iterator over every word in the trie that
+// matches pattern, in lexicographic order. In the pattern, ? matches any one
+// letter and * matches any number of letters, including none.
+func (t *Trie) KeysThatMatch(pattern string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		p := []rune(pattern)
+
+		// Track every position in the pattern we could be at, like an
+		// NFA, so each word only comes up once and stays in order
+		states := make([]bool, len(p)+1)
+		states[0] = true
+		matchClosure(p, states)
+
+		t.match(p, "", states, yield)
+	}
+}
+
+// match yields words below t that finish the pattern from any of states.
+// Returns false if yield asked us to stop.
+func (t *Trie) match(p []rune, word string, states []bool, yield func(string) bool) bool {
+	if t.Value > 0 && states[len(p)] && !yield(word) {
+		return false
+	}
+
+	for child := t.Child; child != nil; child = child.Sibling {
+		next := make([]bool, len(p)+1)
+		alive := false
 
-			// If we have enough words, then stop
-			if len(completions) >= maxWords {
-				return completions
+		for i, ok := range states[:len(p)] {
+			if !ok {
+				continue
 			}
 
-			if !(wordQ.IsFull() || trieQ.IsFull()) {
-				// Add child to queues to process its children
-				wordQ.Enqueue(childWord)
-				trieQ.Enqueue(child)
+			switch p[i] {
+			case '*':
+				next[i] = true
+				alive = true
+			case '?', child.Letter:
+				next[i+1] = true
+				alive = true
 			}
+		}
+
+		// No way to match anything under this child
+		if !alive {
+			continue
+		}
 
-			// Try the next sibling
-			child = child.Sibling
+		matchClosure(p, next)
+
+		if !child.match(p, word+string(child.Letter), next, yield) {
+			return false
 		}
 	}
 
-	return completions
+	return true
+}
+
+// matchClosure adds every position we can reach by skipping a *
+func matchClosure(p []rune, states []bool) {
+	for i := range p {
+		if states[i] && p[i] == '*' {
+			states[i+1] = true
+		}
+	}
 }
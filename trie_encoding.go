@@ -0,0 +1,342 @@
+package algo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+var BadTrieFile = errors.New("data is not a trie file")
+var TrieTooLarge = errors.New("trie has too many nodes to save")
+
+// Every trie file starts with these bytes, then a version
+var trieMagic = [4]byte{'t', 'r', 'i', 'e'}
+
+const (
+	trieVersion    = 1
+	trieHeaderSize = 16
+
+	// Each node is letter, child count, first child, value, weight and
+	// the max weight below it
+	trieRecordSize = 4 + 4 + 4 + 8 + 8 + 8
+)
+
+// WriteTo writes the trie to w in a compact format that can be loaded with
+// ReadTrie or queried in place with NewMappedTrie. After a header with the
+// number of nodes, every node is a fixed size record in breadth-first
+// order, so the children of a node are next to each other in order by
+// letter.
+func (t *Trie) WriteTo(w io.Writer) (int64, error) {
+	var rec [trieRecordSize]byte
+
+	count := t.Stats().Nodes
+	if count > math.MaxUint32 {
+		return 0, TrieTooLarge
+	}
+
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+
+	// Write the header
+	cw.Write(trieMagic[:])
+	binary.Write(cw, binary.LittleEndian, uint32(trieVersion))
+	binary.Write(cw, binary.LittleEndian, uint64(count))
+
+	// The index of the next node we haven't given a spot yet
+	next := 1
+
+	queue := []*Trie{t}
+	for len(queue) > 0 && cw.err == nil {
+		node := queue[0]
+		queue = queue[1:]
+
+		children := 0
+		for child := node.Child; child != nil; child = child.Sibling {
+			queue = append(queue, child)
+			children++
+		}
+
+		binary.LittleEndian.PutUint32(rec[0:], uint32(node.Letter))
+		binary.LittleEndian.PutUint32(rec[4:], uint32(children))
+		binary.LittleEndian.PutUint32(rec[8:], uint32(next))
+		binary.LittleEndian.PutUint64(rec[12:], uint64(node.Value))
+		binary.LittleEndian.PutUint64(rec[20:], uint64(node.Weight))
+		binary.LittleEndian.PutUint64(rec[28:], uint64(node.maxWeight))
+		cw.Write(rec[:])
+
+		next += children
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+
+	return cw.n, bw.Flush()
+}
+
+// ReadTrie loads a trie written by WriteTo
+func ReadTrie(r io.Reader) (*Trie, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	mt, err := NewMappedTrie(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create every node at once, then link them up
+	nodes := make([]Trie, mt.count)
+	for i := range nodes {
+		rec := mt.record(uint32(i))
+
+		nodes[i] = Trie{
+			Letter:    rune(binary.LittleEndian.Uint32(rec[0:])),
+			Value:     int64(binary.LittleEndian.Uint64(rec[12:])),
+			Weight:    int64(binary.LittleEndian.Uint64(rec[20:])),
+			maxWeight: int64(binary.LittleEndian.Uint64(rec[28:])),
+		}
+	}
+
+	for i := range nodes {
+		first, children := mt.children(uint32(i))
+		if children == 0 {
+			continue
+		}
+
+		nodes[i].Child = &nodes[first]
+		for j := first; j < first+children-1; j++ {
+			nodes[j].Sibling = &nodes[j+1]
+		}
+
+		if int(children) > trieIndexFanout {
+			nodes[i].index = &trieIndex{}
+			for j := first; j < first+children; j++ {
+				nodes[i].index.children = append(nodes[i].index.children, &nodes[j])
+			}
+		}
+	}
+
+	return &nodes[0], nil
+}
+
+// MappedTrie is a read only trie that works directly on the bytes written
+// by Trie.WriteTo, without loading them into nodes. Use MapTrie to query a
+// file without reading it all into memory.
+type MappedTrie struct {
+	data  []byte
+	count uint32
+
+	// Release data when we're done, if needed
+	close func() error
+}
+
+// MappedNode is a node in a MappedTrie
+type MappedNode struct {
+	Letter rune
+	Value  int64
+	Weight int64
+
+	trie  *MappedTrie
+	index uint32
+}
+
+type MappedCompletion struct {
+	Word string
+	Node *MappedNode
+}
+
+// NewMappedTrie checks that data was written by Trie.WriteTo and returns a
+// trie that reads from it
+func NewMappedTrie(data []byte) (*MappedTrie, error) {
+	if len(data) < trieHeaderSize || [4]byte(data[0:4]) != trieMagic ||
+		binary.LittleEndian.Uint32(data[4:]) != trieVersion {
+
+		return nil, BadTrieFile
+	}
+
+	count := binary.LittleEndian.Uint64(data[8:])
+	if count < 1 || count > math.MaxUint32 ||
+		uint64(len(data)-trieHeaderSize) != count*trieRecordSize {
+
+		return nil, BadTrieFile
+	}
+
+	mt := &MappedTrie{data: data, count: uint32(count)}
+
+	// Make sure every node but the root has exactly one parent, coming
+	// before it in breadth-first order, and children are sorted. Then we
+	// never have to check again.
+	next := uint64(1)
+	for i := uint32(0); i < mt.count; i++ {
+		first, children := mt.children(i)
+		if children == 0 {
+			continue
+		}
+
+		if uint64(first) != next || next+uint64(children) > count {
+			return nil, BadTrieFile
+		}
+		next += uint64(children)
+
+		for j := first + 1; j < first+children; j++ {
+			if mt.letter(j-1) >= mt.letter(j) {
+				return nil, BadTrieFile
+			}
+		}
+	}
+
+	if next != count {
+		return nil, BadTrieFile
+	}
+
+	return mt, nil
+}
+
+// Close releases the data of the trie, if it came from MapTrie
+func (mt *MappedTrie) Close() error {
+	if mt.close == nil {
+		return nil
+	}
+
+	err := mt.close()
+	mt.close = nil
+	mt.data = nil
+
+	return err
+}
+
+// Stats counts the nodes and words in the trie. Bytes is the size of the
+// data.
+func (mt *MappedTrie) Stats() TrieStats {
+	stats := TrieStats{
+		Nodes: int(mt.count),
+		Bytes: len(mt.data),
+	}
+
+	for i := uint32(0); i < mt.count; i++ {
+		if mt.node(i).Value > 0 {
+			stats.Words++
+		}
+	}
+
+	return stats
+}
+
+// The raw record of node i
+func (mt *MappedTrie) record(i uint32) []byte {
+	offset := trieHeaderSize + int(i)*trieRecordSize
+
+	return mt.data[offset : offset+trieRecordSize]
+}
+
+func (mt *MappedTrie) letter(i uint32) rune {
+	return rune(binary.LittleEndian.Uint32(mt.record(i)))
+}
+
+// The index of the first child of node i and how many children it has
+func (mt *MappedTrie) children(i uint32) (uint32, uint32) {
+	rec := mt.record(i)
+
+	return binary.LittleEndian.Uint32(rec[8:]), binary.LittleEndian.Uint32(rec[4:])
+}
+
+func (mt *MappedTrie) node(i uint32) *MappedNode {
+	rec := mt.record(i)
+
+	return &MappedNode{
+		Letter: rune(binary.LittleEndian.Uint32(rec[0:])),
+		Value:  int64(binary.LittleEndian.Uint64(rec[12:])),
+		Weight: int64(binary.LittleEndian.Uint64(rec[20:])),
+		trie:   mt,
+		index:  i,
+	}
+}
+
+// findChild binary searches the children of node i for letter
+func (mt *MappedTrie) findChild(i uint32, letter rune) (uint32, bool) {
+	first, children := mt.children(i)
+
+	lo, hi := first, first+children
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		switch l := mt.letter(mid); {
+		case l == letter:
+			return mid, true
+		case l < letter:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return 0, false
+}
+
+// Exists returns a boolean indicating whether this word exists or not in our
+// trie. It also returns the node when found.
+func (mt *MappedTrie) Exists(word string) (bool, *MappedNode) {
+	var (
+		i     uint32
+		found bool
+	)
+
+	for _, letter := range word {
+		i, found = mt.findChild(i, letter)
+		if !found {
+			return false, nil
+		}
+	}
+
+	node := mt.node(i)
+
+	return node.Value > 0, node
+}
+
+// FindCompletions does a breadth-first search below this node, and finds up
+// to max completed words under it, like Trie.FindCompletions
+func (n *MappedNode) FindCompletions(word string, maxWords int) []MappedCompletion {
+	var completions []MappedCompletion
+
+	if maxWords < 1 {
+		return completions
+	}
+
+	type queued struct {
+		word  string
+		index uint32
+	}
+
+	queue := []queued{{word, n.index}}
+	for len(queue) > 0 {
+		q := queue[0]
+		queue = queue[1:]
+
+		first, children := n.trie.children(q.index)
+		for i := first; i < first+children; i++ {
+			child := n.trie.node(i)
+			childWord := q.word + string(child.Letter)
+
+			// If it's a word, add it to our words
+			if child.Value > 0 {
+				completions = append(completions, MappedCompletion{
+					Word: childWord,
+					Node: child,
+				})
+
+				// If we have enough words, then stop
+				if len(completions) >= maxWords {
+					return completions
+				}
+			}
+
+			queue = append(queue, queued{childWord, i})
+		}
+	}
+
+	return completions
+}
@@ -0,0 +1,173 @@
+package algo_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brnstz/algo"
+)
+
+func TestTrieEncoding(t *testing.T) {
+	trie, _, words := loadTries("data/tale.txt", t)
+	for i, word := range words {
+		trie.SetWeight(word, int64(i%100))
+	}
+
+	buf := &bytes.Buffer{}
+	n, err := trie.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("Wrote %v bytes but said %v", buf.Len(), n)
+	}
+
+	loaded, err := algo.ReadTrie(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if trie.Stats().Words != loaded.Stats().Words || trie.Stats().Nodes != loaded.Stats().Nodes {
+		t.Fatalf("Expected %+v but got %+v", trie.Stats(), loaded.Stats())
+	}
+
+	// Everything should be the same, in the same order
+	var expected, actual []string
+	for word, node := range trie.All() {
+		expected = append(expected, fmt.Sprint(word, node.Value, node.Weight))
+	}
+	for word, node := range loaded.All() {
+		actual = append(actual, fmt.Sprint(word, node.Value, node.Weight))
+	}
+	if fmt.Sprint(expected) != fmt.Sprint(actual) {
+		t.Fatal("Loaded trie doesn't match")
+	}
+
+	for _, prefix := range []string{"", "t", "wor", "be"} {
+		var a, b []int64
+
+		_, node := trie.Exists(prefix)
+		for _, c := range node.FindTopCompletions(prefix, 10) {
+			a = append(a, c.Node.Weight)
+		}
+		_, node = loaded.Exists(prefix)
+		for _, c := range node.FindTopCompletions(prefix, 10) {
+			b = append(b, c.Node.Weight)
+		}
+
+		if fmt.Sprint(a) != fmt.Sprint(b) {
+			t.Fatalf("Top completions for %q don't match: %v, %v", prefix, a, b)
+		}
+	}
+
+	// The loaded trie should still be usable
+	loaded.Add("zzzzz", 1)
+	loaded.Remove("the")
+	if exists, _ := loaded.Exists("zzzzz"); !exists {
+		t.Fatal("Can't add to loaded trie")
+	}
+	if exists, _ := loaded.Exists("the"); exists {
+		t.Fatal("Can't remove from loaded trie")
+	}
+}
+
+func TestMappedTrie(t *testing.T) {
+	trie, _, words := loadTries("data/tale.txt", t)
+
+	path := filepath.Join(t.TempDir(), "tale.trie")
+	fh, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = trie.WriteTo(fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fh.Close()
+
+	mt, err := algo.MapTrie(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mt.Close()
+
+	if mt.Stats().Words != trie.Stats().Words {
+		t.Fatalf("Expected %v words but got %v", trie.Stats().Words, mt.Stats().Words)
+	}
+
+	for _, word := range append(uniqueWords(words), "qqqqq", "") {
+		for _, prefix := range []string{word, word[:len(word)/2]} {
+			exists, node := trie.Exists(prefix)
+			mappedExists, mappedNode := mt.Exists(prefix)
+
+			if exists != mappedExists || (node == nil) != (mappedNode == nil) {
+				t.Fatalf("%q exists: %v but mapped: %v", prefix, exists, mappedExists)
+			}
+			if node == nil {
+				continue
+			}
+
+			var expected, actual []string
+			for _, c := range node.FindCompletions(prefix, 10) {
+				expected = append(expected, c.Word)
+			}
+			for _, c := range mappedNode.FindCompletions(prefix, 10) {
+				actual = append(actual, c.Word)
+			}
+			if fmt.Sprint(expected) != fmt.Sprint(actual) {
+				t.Fatalf("%q completions: %v but mapped: %v", prefix, expected, actual)
+			}
+		}
+	}
+}
+
+func TestMappedTrieBadData(t *testing.T) {
+	trie := algo.NewTrie()
+	trie.Add("cat", 1)
+	trie.Add("car", 1)
+
+	buf := &bytes.Buffer{}
+	trie.WriteTo(buf)
+	good := buf.Bytes()
+
+	corrupt := func(f func(b []byte) []byte) []byte {
+		return f(bytes.Clone(good))
+	}
+
+	tests := map[string][]byte{
+		"empty":     nil,
+		"magic":     corrupt(func(b []byte) []byte { b[0] = 'x'; return b }),
+		"truncated": corrupt(func(b []byte) []byte { return b[:len(b)-1] }),
+		"count":     corrupt(func(b []byte) []byte { binary.LittleEndian.PutUint64(b[8:], 100); return b }),
+		// Point the root at itself as its first child
+		"cycle": corrupt(func(b []byte) []byte { binary.LittleEndian.PutUint32(b[16+8:], 0); return b }),
+	}
+
+	for name, data := range tests {
+		if _, err := algo.NewMappedTrie(data); err != algo.BadTrieFile {
+			t.Fatalf("%v: expected bad trie file but got %v", name, err)
+		}
+		if _, err := algo.ReadTrie(bytes.NewReader(data)); err != algo.BadTrieFile {
+			t.Fatalf("%v: expected bad trie file but got %v", name, err)
+		}
+	}
+}
+
+// Each word in words once, in the order they first appear
+func uniqueWords(words []string) []string {
+	var unique []string
+
+	seen := map[string]bool{}
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			unique = append(unique, word)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,251 @@
+package algo
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// Letters from a few of the scripts wikisearch sees in titles
+var titleScripts = [][2]rune{
+	{'a', 'z'},
+	{'A', 'Z'},
+	{'0', '9'},
+	{'а', 'я'},
+	{'α', 'ω'},
+	{'一', '一' + 2000},
+	{'ぁ', 'ゖ'},
+	{'가', '가' + 1000},
+}
+
+// Create n random titles, each in one script. With thousands of possible
+// letters, the top of the trie has a very high fanout.
+func randomTitles(n int, r *rand.Rand) []string {
+	titles := make([]string, n)
+
+	for i := range titles {
+		script := titleScripts[r.Intn(len(titleScripts))]
+		length := r.Intn(12) + 1
+
+		title := []rune{}
+		for j := 0; j < length; j++ {
+			title = append(title, script[0]+rune(r.Intn(int(script[1]-script[0]+1))))
+		}
+
+		titles[i] = string(title)
+	}
+
+	return titles
+}
+
+// Check that the children of every node are sorted and match the index
+func checkTrieChildren(t *testing.T, node *Trie) {
+	var children []*Trie
+
+	for child := node.Child; child != nil; child = child.Sibling {
+		if len(children) > 0 && children[len(children)-1].Letter >= child.Letter {
+			t.Fatalf("Children out of order at %q", child.Letter)
+		}
+
+		children = append(children, child)
+		checkTrieChildren(t, child)
+	}
+
+	if node.index != nil {
+		if len(node.index.children) != len(children) {
+			t.Fatalf("Index has %v children but list has %v", len(node.index.children), len(children))
+		}
+
+		for i := range children {
+			if node.index.children[i] != children[i] {
+				t.Fatalf("Index doesn't match list at %v", i)
+			}
+		}
+	}
+}
+
+func TestTrieSortedChildren(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	titles := randomTitles(5000, r)
+
+	trie := NewTrie()
+	expected := map[string]bool{}
+
+	for _, title := range titles {
+		trie.Add(title, 1)
+		expected[title] = true
+	}
+	checkTrieChildren(t, trie)
+
+	if trie.index == nil {
+		t.Fatal("Expected the root to be indexed")
+	}
+
+	// Remove about half
+	for _, title := range titles {
+		if r.Intn(2) == 0 && expected[title] {
+			trie.Remove(title)
+			delete(expected, title)
+		}
+	}
+	checkTrieChildren(t, trie)
+
+	for _, title := range titles {
+		if exists, _ := trie.Exists(title); exists != expected[title] {
+			t.Fatalf("Expected exists to be %v for %q", expected[title], title)
+		}
+	}
+
+	// All should be sorted now without any extra work
+	last := ""
+	count := 0
+	for word := range trie.All() {
+		if count > 0 && word <= last {
+			t.Fatalf("%q came after %q", word, last)
+		}
+		last = word
+		count++
+	}
+	if count != len(expected) {
+		t.Fatalf("Expected %v words but got %v", len(expected), count)
+	}
+}
+
+// Check that every node knows the highest weight at or below it. Returns
+// that weight.
+func checkTrieMaxWeights(t *testing.T, node *Trie) int64 {
+	maxWeight := int64(0)
+	if node.Value > 0 {
+		maxWeight = node.Weight
+	}
+
+	for child := node.Child; child != nil; child = child.Sibling {
+		maxWeight = max(maxWeight, checkTrieMaxWeights(t, child))
+	}
+
+	if node.maxWeight != maxWeight {
+		t.Fatalf("Expected max weight %v at %q but got %v", maxWeight, node.Letter, node.maxWeight)
+	}
+
+	return maxWeight
+}
+
+// Weights going up and down in any order should keep max weights right
+func TestTrieMaxWeights(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	titles := randomTitles(2000, r)
+
+	trie := NewTrie()
+	for i := 0; i < 20000; i++ {
+		title := titles[r.Intn(len(titles))]
+
+		switch r.Intn(4) {
+		case 0:
+			trie.Remove(title)
+		case 1:
+			trie.ClearBits(title, int64(r.Intn(4)))
+		default:
+			trie.Add(title, int64(r.Intn(3)+1))
+			trie.SetWeight(title, int64(r.Intn(20)))
+		}
+
+		if i%100 == 0 {
+			checkTrieMaxWeights(t, trie)
+		}
+	}
+
+	checkTrieMaxWeights(t, trie)
+}
+
+// Compare plain sorted lists with indexing high fanout nodes on titles like
+// the ones wikisearch loads
+func BenchmarkTrie(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	titles := randomTitles(100000, r)
+
+	defer func(fanout int) { trieIndexFanout = fanout }(trieIndexFanout)
+
+	for _, fanout := range []int{math.MaxInt, 16} {
+		name := fmt.Sprintf("index-%v", fanout)
+		if fanout == math.MaxInt {
+			name = "list"
+		}
+
+		trieIndexFanout = fanout
+
+		b.Run(name+"/add", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				trie := NewTrie()
+				for _, title := range titles {
+					trie.Add(title, 1)
+				}
+			}
+		})
+
+		// Rank titles by how many of 60 wikis they're in, the same as
+		// wikisearch
+		b.Run(name+"/add-weighted", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				trie := NewTrie()
+				for j, title := range titles {
+					trie.Add(title, 1<<(j%60))
+
+					_, node := trie.Exists(title)
+					trie.SetWeight(title, int64(bits.OnesCount64(uint64(node.Value))))
+				}
+			}
+		})
+
+		trie := NewTrie()
+		for _, title := range titles {
+			trie.Add(title, 1)
+		}
+
+		b.Run(name+"/exists", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				trie.Exists(titles[i%len(titles)])
+			}
+		})
+
+		b.Run(name+"/completions", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				prefix := []rune(titles[i%len(titles)])[:1]
+				_, node := trie.Exists(string(prefix))
+				node.FindCompletions(string(prefix), 25)
+			}
+		})
+	}
+}
+
+// Each new child copies its parent's children in a ConcurrentTrie, so
+// compare adding to one with adding to a Trie behind a lock, the way
+// wikisearch does with -impl trie
+func BenchmarkConcurrentTrieAdd(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	titles := randomTitles(100000, r)
+
+	b.Run("concurrent", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			trie := NewConcurrentTrie()
+			for _, title := range titles {
+				trie.Add(title, 1)
+			}
+		}
+	})
+
+	b.Run("locked", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var lock sync.Mutex
+
+			trie := NewTrie()
+			for _, title := range titles {
+				lock.Lock()
+				trie.Add(title, 1)
+				lock.Unlock()
+			}
+		}
+	})
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package algo
+
+import (
+	"os"
+)
+
+// MapTrie reads a file written by Trie.WriteTo and returns a trie that reads
+// from it directly. This platform can't memory map files, so the whole file
+// is read into memory.
+func MapTrie(path string) (*MappedTrie, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMappedTrie(data)
+}
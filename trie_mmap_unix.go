@@ -0,0 +1,44 @@
+//go:build unix
+
+package algo
+
+import (
+	"os"
+	"syscall"
+)
+
+// MapTrie memory maps a file written by Trie.WriteTo and returns a trie
+// that reads from it directly. Call Close when done with it.
+func MapTrie(path string) (*MappedTrie, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	info, err := fh.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() < trieHeaderSize {
+		return nil, BadTrieFile
+	}
+
+	data, err := syscall.Mmap(int(fh.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+
+	mt, err := NewMappedTrie(data)
+	if err != nil {
+		syscall.Munmap(data)
+		return nil, err
+	}
+
+	mt.close = func() error {
+		return syscall.Munmap(data)
+	}
+
+	return mt, nil
+}
@@ -0,0 +1,251 @@
+package algo
+
+import (
+	"iter"
+	"sort"
+)
+
+// trieNode is what the searches shared by Trie and ConcurrentTrie need to
+// know about a node. N is the node type itself, which has to be comparable
+// to go in a PriorityQueue.
+type trieNode[N any] interface {
+	comparable
+
+	nodeLetter() rune
+	nodeValue() int64
+	nodeWeight() int64
+
+	// The highest weight of any word at or below the node
+	nodeMaxWeight() int64
+
+	// Roughly how many bytes the node takes up, for Stats
+	nodeBytes() int
+
+	// The children of the node in order by letter
+	nodeChildren() iter.Seq[N]
+}
+
+// findCompletions does a breadth-first search below n, and makes up to
+// maxWords completions out of the words under it
+func findCompletions[N trieNode[N], C any](n N, word string, maxWords int, completion func(word string, node N) C) []C {
+	var completions []C
+
+	if maxWords < 1 {
+		return completions
+	}
+
+	type queued struct {
+		word string
+		node N
+	}
+
+	queue := []queued{{word, n}}
+	for len(queue) > 0 {
+		q := queue[0]
+		queue = queue[1:]
+
+		for child := range q.node.nodeChildren() {
+			childWord := q.word + string(child.nodeLetter())
+
+			// If it's a word, add it to our words
+			if child.nodeValue() > 0 {
+				completions = append(completions, completion(childWord, child))
+
+				// If we have enough words, then stop
+				if len(completions) >= maxWords {
+					return completions
+				}
+			}
+
+			queue = append(queue, queued{childWord, child})
+		}
+	}
+
+	return completions
+}
+
+// A candidate in findTopCompletions. If subtree is true, weight is the best
+// weight of any word below node, otherwise node is a word with this weight.
+type topCompletion[N comparable] struct {
+	word    string
+	node    N
+	weight  int64
+	subtree bool
+}
+
+func topCompletionLess[N comparable](a, b topCompletion[N]) bool {
+	return a.weight < b.weight
+}
+
+// findTopCompletions makes up to maxWords completions out of the words
+// under n with the highest weights, best first
+func findTopCompletions[N trieNode[N], C any](n N, word string, maxWords int, completion func(word string, node N) C) []C {
+	var completions []C
+
+	pq := NewUnboundedPriorityQueue(topCompletionLess[N])
+
+	push := func(word string, node N) {
+		for child := range node.nodeChildren() {
+			pq.Insert(topCompletion[N]{
+				word:    word + string(child.nodeLetter()),
+				node:    child,
+				weight:  child.nodeMaxWeight(),
+				subtree: true,
+			})
+		}
+	}
+
+	push(word, n)
+
+	for len(completions) < maxWords && !pq.IsEmpty() {
+		top, _ := pq.DelMax()
+
+		// Nothing left in the queue can beat a word, so it's next
+		if !top.subtree {
+			completions = append(completions, completion(top.word, top.node))
+			continue
+		}
+
+		// Otherwise, open up the subtree
+		if top.node.nodeValue() > 0 {
+			pq.Insert(topCompletion[N]{
+				word:   top.word,
+				node:   top.node,
+				weight: top.node.nodeWeight(),
+			})
+		}
+
+		push(top.word, top.node)
+	}
+
+	return completions
+}
+
+// trieStats counts the nodes and words at or below n and estimates how
+// much memory they use
+func trieStats[N trieNode[N]](n N) TrieStats {
+	var stats TrieStats
+
+	stack := []N{n}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		stats.Nodes++
+		stats.Bytes += node.nodeBytes()
+		if node.nodeValue() > 0 {
+			stats.Words++
+		}
+
+		for child := range node.nodeChildren() {
+			stack = append(stack, child)
+		}
+	}
+
+	return stats
+}
+
+// A word found by fuzzySearch before it's turned into a match
+type fuzzyCandidate[N any] struct {
+	word  string
+	node  N
+	edits int
+}
+
+// fuzzySearch finds up to limit words at or below root within maxEdits of
+// word, closest first, then by weight. If transpose is true, swapping two
+// letters next to each other is one edit.
+func fuzzySearch[N trieNode[N], M any](root N, word string, maxEdits, limit int, transpose bool, match func(word string, node N, edits int) M) []M {
+	var candidates []fuzzyCandidate[N]
+
+	target := []rune(word)
+
+	// The distance from the empty string to each prefix of word
+	row := make([]int, len(target)+1)
+	for i := range row {
+		row[i] = i
+	}
+
+	if root.nodeValue() > 0 && row[len(target)] <= maxEdits {
+		candidates = append(candidates, fuzzyCandidate[N]{node: root, edits: row[len(target)]})
+	}
+
+	for child := range root.nodeChildren() {
+		fuzzyWalk(child, target, string(child.nodeLetter()), 0, nil, row, maxEdits, transpose, &candidates)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].edits != candidates[j].edits {
+			return candidates[i].edits < candidates[j].edits
+		}
+
+		return candidates[i].node.nodeWeight() > candidates[j].node.nodeWeight()
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:max(limit, 0)]
+	}
+
+	var matches []M
+	for _, c := range candidates {
+		matches = append(matches, match(c.word, c.node, c.edits))
+	}
+
+	return matches
+}
+
+// fuzzyRow fills in the edit distance row for a node with this letter from
+// its parent's row. We only need the grandparent's row and the parent's
+// letter to check for swaps. Returns the row and the lowest distance in it.
+func fuzzyRow(target []rune, letter, parentLetter rune, grandRow, parentRow []int, transpose bool) ([]int, int) {
+	row := make([]int, len(parentRow))
+	row[0] = parentRow[0] + 1
+	best := row[0]
+
+	for j := 1; j < len(row); j++ {
+		cost := 1
+		if target[j-1] == letter {
+			cost = 0
+		}
+
+		row[j] = min(
+			parentRow[j]+1,
+			row[j-1]+1,
+			parentRow[j-1]+cost,
+		)
+
+		if transpose && grandRow != nil && j > 1 &&
+			target[j-1] == parentLetter && target[j-2] == letter {
+
+			row[j] = min(row[j], grandRow[j-2]+1)
+		}
+
+		best = min(best, row[j])
+	}
+
+	return row, best
+}
+
+// fuzzyWalk fills in the edit distance row for n from its parent's row,
+// and keeps going down as long as some prefix of target is still within
+// maxEdits
+func fuzzyWalk[N trieNode[N]](n N, target []rune, word string, parentLetter rune, grandRow, parentRow []int, maxEdits int, transpose bool, candidates *[]fuzzyCandidate[N]) {
+	row, best := fuzzyRow(target, n.nodeLetter(), parentLetter, grandRow, parentRow, transpose)
+
+	if n.nodeValue() > 0 && row[len(target)] <= maxEdits {
+		*candidates = append(*candidates, fuzzyCandidate[N]{
+			word:  word,
+			node:  n,
+			edits: row[len(target)],
+		})
+	}
+
+	// If every prefix is already too far away, nothing below can match
+	if best > maxEdits {
+		return
+	}
+
+	for child := range n.nodeChildren() {
+		fuzzyWalk(child, target, word+string(child.nodeLetter()), n.nodeLetter(), parentRow, row, maxEdits, transpose, candidates)
+	}
+}
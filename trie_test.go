@@ -0,0 +1,369 @@
+package algo_test
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/brnstz/algo"
+)
+
+// Count every node under t, including t
+func countTrieNodes(t *algo.Trie) int {
+	if t == nil {
+		return 0
+	}
+
+	return 1 + countTrieNodes(t.Child) + countTrieNodes(t.Sibling)
+}
+
+func TestTrieRemove(t *testing.T) {
+	trie := algo.NewTrie()
+
+	trie.Add("car", 1)
+	trie.Add("cart", 2)
+	trie.Add("cat", 4)
+	trie.Add("dog", 8)
+
+	// Root + c, a, r, t, t + d, o, g
+	if countTrieNodes(trie) != 9 {
+		t.Fatalf("Expected 9 nodes but got %v", countTrieNodes(trie))
+	}
+
+	// Removing a prefix that isn't a word does nothing
+	exists, removed := trie.Remove("ca")
+	if exists || removed != 0 {
+		t.Fatalf("Removed non-word: %v, %v", exists, removed)
+	}
+
+	// car still leads to cart, so nothing is pruned
+	exists, removed = trie.Remove("car")
+	if !exists || removed != 0 {
+		t.Fatalf("Expected to remove car with no pruning, got %v, %v", exists, removed)
+	}
+	if exists, _ = trie.Exists("car"); exists {
+		t.Fatal("car still exists")
+	}
+	if exists, _ = trie.Exists("cart"); !exists {
+		t.Fatal("cart is gone")
+	}
+
+	// Now r and t are both unused
+	exists, removed = trie.Remove("cart")
+	if !exists || removed != 2 {
+		t.Fatalf("Expected to prune 2 nodes for cart, got %v, %v", exists, removed)
+	}
+
+	// The whole dog branch goes away, and cat should be left alone
+	exists, removed = trie.Remove("dog")
+	if !exists || removed != 3 {
+		t.Fatalf("Expected to prune 3 nodes for dog, got %v, %v", exists, removed)
+	}
+	if exists, _ = trie.Exists("cat"); !exists {
+		t.Fatal("cat is gone")
+	}
+	if countTrieNodes(trie) != 4 {
+		t.Fatalf("Expected 4 nodes but got %v", countTrieNodes(trie))
+	}
+
+	exists, removed = trie.Remove("cat")
+	if !exists || removed != 3 || trie.Child != nil {
+		t.Fatalf("Expected empty trie, got %v, %v", exists, removed)
+	}
+}
+
+func TestTrieSetValue(t *testing.T) {
+	trie := algo.NewTrie()
+
+	trie.Add("one", 1|2|4)
+	trie.Add("ones", 8)
+
+	if exists, _ := trie.SetValue("on", 1); exists {
+		t.Fatal("Set value of non-word")
+	}
+
+	exists, _ := trie.SetValue("one", 16)
+	_, node := trie.Exists("one")
+	if !exists || node.Value != 16 {
+		t.Fatalf("Expected value 16 but got %v", node.Value)
+	}
+
+	exists, _ = trie.ClearBits("one", 1)
+	_, node = trie.Exists("one")
+	if !exists || node.Value != 16 {
+		t.Fatalf("Clearing unset bits changed value to %v", node.Value)
+	}
+
+	// Clearing the last bit removes the word
+	exists, removed := trie.ClearBits("ones", 8)
+	if !exists || removed != 1 {
+		t.Fatalf("Expected to prune 1 node, got %v, %v", exists, removed)
+	}
+	if exists, _ = trie.Exists("ones"); exists {
+		t.Fatal("ones still exists")
+	}
+
+	trie.Add("one", 32)
+	_, node = trie.Exists("one")
+	if node.Value != 16|32 {
+		t.Fatalf("Expected value 48 but got %v", node.Value)
+	}
+}
+
+// Top completions should match sorting every completion by weight
+func TestTrieTopCompletions(t *testing.T) {
+	numWords := 3000
+	r := rand.New(rand.NewSource(1))
+
+	trie := algo.NewTrie()
+	weights := map[string]int64{}
+
+	for i := 0; i < numWords; i++ {
+		word := ""
+		for j := r.Intn(6) + 1; j > 0; j-- {
+			word += string(rune('a' + r.Intn(4)))
+		}
+
+		trie.Add(word, 1)
+		weights[word] = int64(r.Intn(1000))
+		trie.SetWeight(word, weights[word])
+	}
+
+	if trie.SetWeight("zzz", 1) {
+		t.Fatal("Set weight of word not in trie")
+	}
+
+	check := func(prefix string, k int) {
+		var expected []int64
+		for word, weight := range weights {
+			if len(word) > len(prefix) && strings.HasPrefix(word, prefix) {
+				expected = append(expected, weight)
+			}
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i] > expected[j] })
+		if len(expected) > k {
+			expected = expected[:k]
+		}
+
+		_, node := trie.Exists(prefix)
+		if node == nil {
+			if len(expected) > 0 {
+				t.Fatalf("Can't find %q", prefix)
+			}
+			return
+		}
+
+		completions := node.FindTopCompletions(prefix, k)
+		if len(completions) != len(expected) {
+			t.Fatalf("Expected %v completions for %q but got %v", len(expected), prefix, len(completions))
+		}
+
+		for i, c := range completions {
+			if !strings.HasPrefix(c.Word, prefix) || weights[c.Word] != c.Node.Weight {
+				t.Fatalf("Unexpected completion %q for %q", c.Word, prefix)
+			}
+			if c.Node.Weight != expected[i] {
+				t.Fatalf("Expected weight %v at %v for %q but got %v", expected[i], i, prefix, c.Node.Weight)
+			}
+		}
+	}
+
+	for _, prefix := range []string{"", "a", "ab", "dcb", "abcd"} {
+		check(prefix, 10)
+		check(prefix, 1)
+	}
+
+	// Removing the best words should let the next best through
+	for i := 0; i < 5; i++ {
+		best := trie.FindTopCompletions("", 1)[0].Word
+		trie.Remove(best)
+		delete(weights, best)
+		check("", 10)
+	}
+
+	// Lowering a weight should too
+	best := trie.FindTopCompletions("", 1)[0].Word
+	trie.SetWeight(best, 0)
+	weights[best] = 0
+	check("", 10)
+	check(best[:1], 10)
+}
+
+// Edit distance between a and b the slow way. If transpose is true, swapping
+// two letters next to each other counts as one edit.
+func editDistance(a, b string, transpose bool) int {
+	x, y := []rune(a), []rune(b)
+
+	d := make([][]int, len(x)+1)
+	for i := range d {
+		d[i] = make([]int, len(y)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if transpose && i > 1 && j > 1 && x[i-1] == y[j-2] && x[i-2] == y[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(x)][len(y)]
+}
+
+func TestTrieFuzzySearch(t *testing.T) {
+	trie, _, all := loadTries("data/tale.txt", t)
+
+	// Only check each word once
+	seen := map[string]bool{}
+	var words []string
+	for _, word := range all {
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+
+	queries := []string{"", "the", "teh", "wrost", "bset", "tiems", "citiess", "xyzzy", "époque"}
+
+	for _, transpose := range []bool{false, true} {
+		for _, query := range queries {
+			for maxEdits := 0; maxEdits <= 2; maxEdits++ {
+				expected := map[string]int{}
+				for _, word := range words {
+					d := editDistance(query, word, transpose)
+					if d <= maxEdits {
+						expected[word] = d
+					}
+				}
+
+				var matches []algo.FuzzyMatch
+				if transpose {
+					matches = trie.DamerauFuzzySearch(query, maxEdits, len(words))
+				} else {
+					matches = trie.FuzzySearch(query, maxEdits, len(words))
+				}
+
+				if len(matches) != len(expected) {
+					t.Fatalf("Expected %v matches for %q within %v but got %v", len(expected), query, maxEdits, len(matches))
+				}
+
+				for i, m := range matches {
+					d, ok := expected[m.Word]
+					if !ok || d != m.Edits {
+						t.Fatalf("Unexpected match %+v for %q", m, query)
+					}
+					if i > 0 && matches[i-1].Edits > m.Edits {
+						t.Fatalf("Matches for %q are out of order", query)
+					}
+				}
+
+				if len(expected) > 1 && len(trie.FuzzySearch(query, maxEdits, 1)) != 1 {
+					t.Fatalf("Expected limit of 1 match for %q", query)
+				}
+			}
+		}
+	}
+
+	// A swap is one edit for Damerau and two otherwise
+	found := false
+	for _, m := range trie.DamerauFuzzySearch("teh", 1, len(words)) {
+		found = found || m.Word == "the"
+	}
+	if !found {
+		t.Fatal("Expected to find the within 1 edit of teh")
+	}
+	for _, m := range trie.FuzzySearch("teh", 1, len(words)) {
+		if m.Word == "the" {
+			t.Fatal("Found the within 1 edit of teh without transpositions")
+		}
+	}
+}
+
+func TestTrieIterators(t *testing.T) {
+	words := []string{"she", "sells", "sea", "shells", "by", "the", "sea", "shore", "shell", "s", "cat", "cot", "coat", "ct"}
+
+	trie := algo.NewTrie()
+	for _, word := range words {
+		trie.Add(word, 1)
+	}
+
+	sorted := slices.Clone(words)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	var all []string
+	for word, node := range trie.All() {
+		if node.Value != 1 {
+			t.Fatalf("Unexpected value %v for %q", node.Value, word)
+		}
+		all = append(all, word)
+	}
+	if !slices.Equal(all, sorted) {
+		t.Fatalf("Expected %v but got %v", sorted, all)
+	}
+
+	// Stopping early should work
+	for range trie.All() {
+		break
+	}
+
+	prefixTests := map[string][]string{
+		"sh":    {"she", "shell", "shells", "shore"},
+		"shell": {"shell", "shells"},
+		"x":     nil,
+		"":      sorted,
+	}
+	for prefix, expected := range prefixTests {
+		actual := slices.Collect(trie.KeysWithPrefix(prefix))
+		if !slices.Equal(actual, expected) {
+			t.Fatalf("Expected %v with prefix %q but got %v", expected, prefix, actual)
+		}
+	}
+
+	longestTests := map[string]string{
+		"shellfish": "shell",
+		"shells":    "shells",
+		"sh":        "s",
+		"bye":       "by",
+		"xyz":       "",
+	}
+	for s, expected := range longestTests {
+		actual, node := trie.LongestPrefixOf(s)
+		if actual != expected || (expected == "") != (node == nil) {
+			t.Fatalf("Expected longest prefix %q of %q but got %q", expected, s, actual)
+		}
+	}
+
+	matchTests := map[string][]string{
+		"c?t":   {"cat", "cot"},
+		"c*t":   {"cat", "coat", "cot", "ct"},
+		"sh*":   {"she", "shell", "shells", "shore"},
+		"*ll*":  {"sells", "shell", "shells"},
+		"s??":   {"sea", "she"},
+		"*":     sorted,
+		"**e**": {"sea", "sells", "she", "shell", "shells", "shore", "the"},
+		"?":     {"s"},
+		"c?":    {"ct"},
+		"b??":   nil,
+		"the":   {"the"},
+	}
+	for pattern, expected := range matchTests {
+		actual := slices.Collect(trie.KeysThatMatch(pattern))
+		if !slices.Equal(actual, expected) {
+			t.Fatalf("Expected %v to match %q but got %v", expected, pattern, actual)
+		}
+	}
+}